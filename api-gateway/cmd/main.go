@@ -1,25 +1,117 @@
 package main
 
 import (
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/config"
 	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/grpc_clients"
 	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/middleware"
 	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/routes"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/jwks"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/rbac"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/revocation"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/shutdown"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := shutdown.Context()
+	defer stop()
+
 	r := gin.Default()
-	authRoutes := r.Group("/", middleware.AuthMiddleware())
+	// request_id попадает в ответы с ошибкой и в лог gateway
+	r.Use(middleware.RequestID())
+	// адрес клиента нужен для ограничения перебора паролей, поэтому
+	// X-Forwarded-For принимаем только от явно указанных прокси
+	if err := r.SetTrustedProxies(trustedProxies(cfg.TrustedProxies)); err != nil {
+		log.Fatalf("неверный trusted_proxies: %v", err)
+	}
+
+	identityKey := []byte(cfg.Gateway.IdentityKey)
+	grpc_clients.InitAuthClient(cfg.UserService.Addr, identityKey)
+	grpc_clients.InitTaskClient(cfg.TaskService.Addr, identityKey)
+
+	// публичные ключи проверки токенов берём у user-service и обновляем раз в 5 минут
+	keys := jwks.NewRemote(grpc_clients.FetchJWKS)
+	go keys.Run(ctx, 5*time.Minute)
 
-	grpc_clients.InitAuthClient()
-	grpc_clients.InitTaskClient()
+	revoked := revocation.New(cfg.Redis.Addr)
+	authRoutes := r.Group("/", middleware.AuthMiddleware(keys.Keyfunc, revoked, grpc_clients.AuthenticatePersonalToken))
+
+	r.GET("/healthz", routes.HealthzHandler)
+	r.GET("/readyz", routes.ReadyzHandler(revoked))
+	r.GET("/.well-known/jwks.json", routes.JWKSHandler(keys))
 
 	r.POST("/login", routes.LoginHandler)
+	r.POST("/login/2fa", routes.VerifySecondFactorHandler)
 	r.POST("/register", routes.RegisterHandler)
-	r.GET("/tasks", routes.TasksListHandler)
-	r.POST("tasks", routes.CreateTask)
+	r.POST("/refresh", routes.RefreshHandler)
+	r.POST("/password/forgot", routes.RequestPasswordResetHandler)
+	r.POST("/password/reset", routes.ResetPasswordHandler)
+	r.POST("/email/verify", routes.VerifyEmailHandler)
+	r.POST("/email/resend", routes.ResendVerificationHandler)
 
+	authRoutes.POST("/logout", routes.LogoutHandler(revoked))
 	authRoutes.GET("/profile", routes.ProfileHandler)
+	authRoutes.PATCH("/profile", routes.UpdateProfileHandler)
+	authRoutes.POST("/profile/password", routes.ChangePasswordHandler)
+	authRoutes.DELETE("/profile", routes.DeleteAccountHandler(revoked))
+	authRoutes.GET("/profile/sessions", routes.ListSessionsHandler)
+	authRoutes.DELETE("/profile/sessions/:id", routes.RevokeSessionHandler)
+	authRoutes.POST("/2fa/enroll", routes.EnrollTOTPHandler)
+	authRoutes.POST("/2fa/confirm", routes.ConfirmTOTPHandler)
+	authRoutes.POST("/2fa/disable", routes.DisableTOTPHandler)
+	authRoutes.POST("/tokens", routes.CreatePersonalTokenHandler)
+	authRoutes.GET("/tokens", routes.ListPersonalTokensHandler)
+	authRoutes.DELETE("/tokens/:id", routes.RevokePersonalTokenHandler)
+	authRoutes.PUT("/users/:id/role", middleware.RequirePermission(rbac.UsersManage), routes.SetUserRoleHandler)
+
+	authRoutes.POST("/workspaces", routes.CreateWorkspaceHandler)
+	authRoutes.GET("/workspaces", routes.ListWorkspacesHandler)
+	authRoutes.GET("/workspaces/:id/members", routes.WorkspaceMembersHandler)
+	authRoutes.POST("/workspaces/:id/invitations", routes.InviteMemberHandler)
+	authRoutes.GET("/workspaces/:id/invitations", routes.ListInvitationsHandler)
+	authRoutes.DELETE("/invitations/:id", routes.RevokeInvitationHandler)
+	authRoutes.POST("/invitations/accept", routes.AcceptInvitationHandler)
+
+	// задачи всегда живут в пространстве из заголовка X-Workspace-ID
+	taskRoutes := authRoutes.Group("/tasks", middleware.RequireWorkspace(grpc_clients.IsWorkspaceMember))
+	taskRoutes.GET("", middleware.RequirePermission(rbac.TasksRead), routes.TasksListHandler)
+	taskRoutes.POST("", middleware.RequirePermission(rbac.TasksCreate), routes.CreateTask)
+	taskRoutes.GET("/:id", middleware.RequirePermission(rbac.TasksRead), routes.GetTaskHandler)
+	taskRoutes.PATCH("/:id", middleware.RequirePermission(rbac.TasksUpdate), routes.UpdateTaskHandler)
+	taskRoutes.DELETE("/:id", middleware.RequirePermission(rbac.TasksDelete), routes.DeleteTaskHandler)
+	taskRoutes.POST("/:id/transition", middleware.RequirePermission(rbac.TasksUpdate), routes.TransitionTaskHandler)
+	taskRoutes.POST("/:id/assign", middleware.RequirePermission(rbac.TasksAssign), routes.AssignTaskHandler)
+
+	srv := &http.Server{Addr: cfg.HTTPAddr, Handler: r}
+	log.Printf("API gateway запущен на %s", cfg.HTTPAddr)
+	if err := shutdown.ServeHTTP(ctx, srv, cfg.ShutdownTimeout); err != nil {
+		log.Fatalf("ошибка запуска HTTP сервера: %v", err)
+	}
+	if err := grpc_clients.Close(); err != nil {
+		log.Printf("ошибка закрытия gRPC соединений: %v", err)
+	}
+	log.Println("API gateway остановлен")
+}
 
-	r.Run(":8081")
+// trustedProxies убирает пустые элементы и пробелы: из окружения список
+// приходит строкой через запятую.
+func trustedProxies(list []string) []string {
+	var proxies []string
+	for _, p := range list {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
+	}
+	return proxies
 }
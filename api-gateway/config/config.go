@@ -0,0 +1,39 @@
+// Package config — настройки api-gateway.
+package config
+
+import (
+	"time"
+
+	pkgconfig "github.com/Murodkadirkhanoff/taqsym.uz/pkg/config"
+)
+
+type Config struct {
+	HTTPAddr string `mapstructure:"http_addr" default:":8081" usage:"адрес HTTP сервера"`
+	// ShutdownTimeout — сколько ждать завершения начатых запросов при остановке.
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout" default:"15s" usage:"ожидание запросов при остановке"`
+	Redis           struct {
+		// Addr — Redis, общий с user-service, со списком отозванных токенов и сессий.
+		Addr string `mapstructure:"addr" validate:"required" usage:"адрес Redis"`
+	} `mapstructure:"redis"`
+	Gateway struct {
+		// IdentityKey — ключ, которым gateway подписывает личность пользователя для сервисов.
+		IdentityKey string `mapstructure:"identity_key" validate:"required"`
+	} `mapstructure:"gateway"`
+	UserService struct {
+		Addr string `mapstructure:"addr" default:"localhost:50051" usage:"адрес user-service"`
+	} `mapstructure:"user_service"`
+	TaskService struct {
+		Addr string `mapstructure:"addr" default:"localhost:50052" usage:"адрес task-service"`
+	} `mapstructure:"task_service"`
+	// TrustedProxies — прокси, которым можно доверить X-Forwarded-For.
+	TrustedProxies []string `mapstructure:"trusted_proxies" usage:"адреса или подсети доверенных прокси"`
+}
+
+// Load читает настройки из файла, окружения и флагов args.
+func Load(args []string) (*Config, error) {
+	var cfg Config
+	if err := pkgconfig.Load("api-gateway", &cfg, args); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
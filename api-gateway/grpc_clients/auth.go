@@ -1,20 +1,72 @@
 package grpc_clients
 
 import (
+	"context"
 	"log"
 
 	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
 
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/grpcauth"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/jwks"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
-var AuthClient authpb.AuthServiceClient
+var (
+	AuthClient authpb.AuthServiceClient
+	AuthHealth healthpb.HealthClient
+	authConn   *grpc.ClientConn
+)
 
-func InitAuthClient() {
-	conn, err := grpc.NewClient("user-service:50051", grpc.WithTransportCredentials(insecure.NewCredentials()))
+func InitAuthClient(addr string, identityKey []byte) {
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(grpcauth.UnaryClientInterceptor(identityKey)),
+	)
 	if err != nil {
 		log.Fatalf("не удалось подключиться к auth-service: %v", err)
 	}
+	authConn = conn
+	AuthHealth = healthpb.NewHealthClient(conn)
 	AuthClient = authpb.NewAuthServiceClient(conn)
 }
+
+// FetchJWKS получает у user-service публичные ключи проверки токенов.
+func FetchJWKS(ctx context.Context) ([]jwks.JWK, error) {
+	resp, err := AuthClient.JWKS(ctx, &authpb.JWKSRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]jwks.JWK, 0, len(resp.Keys))
+	for _, k := range resp.Keys {
+		keys = append(keys, jwks.JWK{Kty: k.Kty, Kid: k.Kid, Use: k.Use, Alg: k.Alg, Crv: k.Crv, X: k.X})
+	}
+	return keys, nil
+}
+
+// IsWorkspaceMember проверяет у user-service, состоит ли пользователь из ctx в пространстве.
+func IsWorkspaceMember(ctx context.Context, workspaceID int64) (bool, error) {
+	resp, err := AuthClient.GetMembership(ctx, &authpb.GetMembershipRequest{WorkspaceId: workspaceID})
+	if err != nil {
+		return false, err
+	}
+	return resp.Member, nil
+}
+
+// AuthenticatePersonalToken проверяет personal access token у user-service
+// и возвращает пользователя с правами, урезанными до scopes токена.
+func AuthenticatePersonalToken(ctx context.Context, token string) (*grpcauth.Principal, error) {
+	resp, err := AuthClient.AuthenticatePersonalToken(ctx, &authpb.AuthenticatePersonalTokenRequest{Token: token})
+	if err != nil {
+		return nil, err
+	}
+	return &grpcauth.Principal{
+		UserID:          resp.UserId,
+		Email:           resp.Email,
+		Role:            resp.Role,
+		Permissions:     resp.Permissions,
+		PersonalTokenID: resp.TokenId,
+	}, nil
+}
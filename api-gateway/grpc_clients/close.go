@@ -0,0 +1,19 @@
+package grpc_clients
+
+import (
+	"errors"
+
+	"google.golang.org/grpc"
+)
+
+// Close закрывает соединения с сервисами. Вызывается после остановки
+// HTTP сервера, когда запросов, использующих клиентов, уже нет.
+func Close() error {
+	var errs []error
+	for _, conn := range []*grpc.ClientConn{authConn, taskConn} {
+		if conn != nil {
+			errs = append(errs, conn.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -5,16 +5,27 @@ import (
 
 	taskpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/task"
 
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/grpcauth"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
-var TaskClient taskpb.TaskServiceClient
+var (
+	TaskClient taskpb.TaskServiceClient
+	TaskHealth healthpb.HealthClient
+	taskConn   *grpc.ClientConn
+)
 
-func InitTaskClient() {
-	conn, err := grpc.NewClient("task-service:50051", grpc.WithTransportCredentials(insecure.NewCredentials()))
+func InitTaskClient(addr string, identityKey []byte) {
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(grpcauth.UnaryClientInterceptor(identityKey)),
+	)
 	if err != nil {
-		log.Fatalf("не удалось подключиться к auth-service: %v", err)
+		log.Fatalf("не удалось подключиться к task-service: %v", err)
 	}
+	taskConn = conn
+	TaskHealth = healthpb.NewHealthClient(conn)
 	TaskClient = taskpb.NewTaskServiceClient(conn)
 }
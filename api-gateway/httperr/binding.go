@@ -0,0 +1,83 @@
+package httperr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+)
+
+func init() {
+	// в ошибках валидации поля называем так же, как клиент их передал
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(fieldName)
+	}
+}
+
+func fieldName(f reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// Bind отвечает 400 на ошибку ShouldBindJSON/ShouldBindQuery:
+// ошибки валидации и типов раскладываются по полям.
+func Bind(c *gin.Context, err error) {
+	var (
+		validation validator.ValidationErrors
+		typeErr    *json.UnmarshalTypeError
+		syntaxErr  *json.SyntaxError
+	)
+	switch {
+	case errors.As(err, &validation):
+		e := Error{Reason: "VALIDATION_FAILED", Message: "request validation failed"}
+		for _, fe := range validation {
+			e.Violations = append(e.Violations, Violation{Field: fieldPath(fe), Description: describe(fe)})
+		}
+		write(c, codes.InvalidArgument, e)
+	case errors.As(err, &typeErr):
+		write(c, codes.InvalidArgument, Error{
+			Reason:  "INVALID_TYPE",
+			Message: "request validation failed",
+			Violations: []Violation{{
+				Field:       typeErr.Field,
+				Description: fmt.Sprintf("%s must be %s", typeErr.Field, typeErr.Type),
+			}},
+		})
+	case errors.As(err, &syntaxErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		Abort(c, codes.InvalidArgument, "MALFORMED_BODY", "malformed JSON body")
+	default:
+		Abort(c, codes.InvalidArgument, "MALFORMED_REQUEST", err.Error())
+	}
+}
+
+// fieldPath возвращает путь поля без имени корневой структуры: team[0].user_id.
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if _, rest, ok := strings.Cut(ns, "."); ok {
+		return rest
+	}
+	return ns
+}
+
+func describe(fe validator.FieldError) string {
+	field := fieldPath(fe)
+	if fe.Tag() == "required" {
+		return field + " is required"
+	}
+	return fmt.Sprintf("%s failed %q validation", field, fe.Tag())
+}
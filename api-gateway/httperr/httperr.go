@@ -0,0 +1,125 @@
+// Package httperr отдаёт ошибки gateway клиенту в едином JSON-конверте:
+//
+//	{"error": {"code": "NOT_FOUND", "reason": "TASK_NOT_FOUND", "message": "task not found",
+//	  "violations": [{"field": "title", "description": "title is required"}],
+//	  "request_id": "..."}}
+//
+// code — gRPC-код в виде UPPER_SNAKE_CASE, reason — машинная причина из
+// errdetails.ErrorInfo сервиса, violations — ошибки отдельных полей.
+package httperr
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Violation — ошибка в конкретном поле запроса.
+type Violation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// Error — тело ответа с ошибкой.
+type Error struct {
+	Code       string      `json:"code"`
+	Reason     string      `json:"reason,omitempty"`
+	Message    string      `json:"message"`
+	Violations []Violation `json:"violations,omitempty"`
+	RequestID  string      `json:"request_id,omitempty"`
+}
+
+type mapping struct {
+	http int
+	name string
+}
+
+var codeMapping = map[codes.Code]mapping{
+	codes.Canceled:           {http.StatusBadRequest, "CANCELLED"},
+	codes.Unknown:            {http.StatusInternalServerError, "UNKNOWN"},
+	codes.InvalidArgument:    {http.StatusBadRequest, "INVALID_ARGUMENT"},
+	codes.DeadlineExceeded:   {http.StatusGatewayTimeout, "DEADLINE_EXCEEDED"},
+	codes.NotFound:           {http.StatusNotFound, "NOT_FOUND"},
+	codes.AlreadyExists:      {http.StatusConflict, "ALREADY_EXISTS"},
+	codes.PermissionDenied:   {http.StatusForbidden, "PERMISSION_DENIED"},
+	codes.ResourceExhausted:  {http.StatusTooManyRequests, "RESOURCE_EXHAUSTED"},
+	codes.FailedPrecondition: {http.StatusConflict, "FAILED_PRECONDITION"},
+	codes.Aborted:            {http.StatusConflict, "ABORTED"},
+	codes.OutOfRange:         {http.StatusBadRequest, "OUT_OF_RANGE"},
+	codes.Unimplemented:      {http.StatusNotImplemented, "UNIMPLEMENTED"},
+	codes.Internal:           {http.StatusInternalServerError, "INTERNAL"},
+	codes.Unavailable:        {http.StatusServiceUnavailable, "UNAVAILABLE"},
+	codes.DataLoss:           {http.StatusInternalServerError, "DATA_LOSS"},
+	codes.Unauthenticated:    {http.StatusUnauthorized, "UNAUTHENTICATED"},
+}
+
+// HTTPStatus возвращает HTTP-статус для ошибки gRPC-вызова.
+func HTTPStatus(err error) int {
+	if m, ok := codeMapping[status.Code(err)]; ok {
+		return m.http
+	}
+	return http.StatusInternalServerError
+}
+
+// Respond отвечает ошибкой, которую вернул сервис. Причина и ошибки полей
+// берутся из деталей статуса. Текст серверных ошибок клиенту не показываем,
+// он остаётся в логе вместе с request_id.
+func Respond(c *gin.Context, err error) {
+	st := status.Convert(err)
+	e := Error{Message: st.Message()}
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			e.Reason = d.GetReason()
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				e.Violations = append(e.Violations, Violation{Field: v.GetField(), Description: v.GetDescription()})
+			}
+		}
+	}
+
+	code := HTTPStatus(err)
+	if code >= http.StatusInternalServerError {
+		log.Printf("ошибка сервиса [%s] %s %s: %v", requestID(c), c.Request.Method, c.FullPath(), err)
+		switch st.Code() {
+		case codes.Unavailable:
+			e.Message = "service unavailable"
+		case codes.DeadlineExceeded:
+			e.Message = "service timeout"
+		default:
+			e.Message = "internal error"
+		}
+	}
+	write(c, st.Code(), e)
+}
+
+// Abort отвечает ошибкой, обнаруженной в самом gateway.
+func Abort(c *gin.Context, code codes.Code, reason, message string) {
+	write(c, code, Error{Reason: reason, Message: message})
+}
+
+// InvalidField отвечает 400 с ошибкой в одном поле, например в id из пути.
+func InvalidField(c *gin.Context, field, message string) {
+	write(c, codes.InvalidArgument, Error{
+		Message:    message,
+		Violations: []Violation{{Field: field, Description: message}},
+	})
+}
+
+func write(c *gin.Context, code codes.Code, e Error) {
+	m, ok := codeMapping[code]
+	if !ok {
+		m = codeMapping[codes.Internal]
+	}
+	e.Code = m.name
+	e.RequestID = requestID(c)
+	c.AbortWithStatusJSON(m.http, gin.H{"error": e})
+}
+
+func requestID(c *gin.Context) string {
+	return c.GetString("requestID")
+}
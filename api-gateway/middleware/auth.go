@@ -1,65 +1,143 @@
 package middleware
 
 import (
-	"net/http"
+	"context"
 	"strings"
 
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/httperr"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/grpcauth"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/revocation"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-var secret = []byte("supersecret")
+// PersonalTokenFunc проверяет personal access token у user-service.
+// Для неизвестного, отозванного или истёкшего токена возвращает Unauthenticated.
+type PersonalTokenFunc func(ctx context.Context, token string) (*grpcauth.Principal, error)
 
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware проверяет JWT и что ни он, ни его сессия не отозваны.
+// keyfunc находит публичный ключ по kid, например jwks.Remote.Keyfunc.
+// Вместо JWT можно передать personal access token, его проверяет personalToken;
+// права такого запроса ограничены scopes токена.
+func AuthMiddleware(keyfunc jwt.Keyfunc, revoked revocation.Store, personalToken PersonalTokenFunc) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Токен отсутствует"})
-			c.Abort()
+			httperr.Abort(c, codes.Unauthenticated, "MISSING_TOKEN", "Токен отсутствует")
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Неверный формат токена"})
-			c.Abort()
+			httperr.Abort(c, codes.Unauthenticated, "INVALID_TOKEN", "Неверный формат токена")
 			return
 		}
 
 		tokenStr := parts[1]
 
-		// Парсим токен
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			// Проверка метода подписи
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrInvalidKey
+		if strings.HasPrefix(tokenStr, grpcauth.PersonalTokenPrefix) {
+			p, err := personalToken(c.Request.Context(), tokenStr)
+			if status.Code(err) == codes.Unauthenticated {
+				httperr.Abort(c, codes.Unauthenticated, "INVALID_TOKEN", "Невалидный токен")
+				return
 			}
-			return secret, nil
-		})
+			if err != nil {
+				httperr.Abort(c, codes.Unavailable, "", "не удалось проверить токен")
+				return
+			}
+
+			setPrincipal(c, p)
+			c.Next()
+			return
+		}
+
+		// Парсим токен, принимаем только подпись EdDSA
+		token, err := jwt.Parse(tokenStr, keyfunc,
+			jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}))
 
 		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Невалидный токен"})
-			c.Abort()
+			httperr.Abort(c, codes.Unauthenticated, "INVALID_TOKEN", "Невалидный токен")
 			return
 		}
 
 		// Получаем userID из claims
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if !ok || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Ошибка claims"})
-			c.Abort()
+			httperr.Abort(c, codes.Unauthenticated, "INVALID_TOKEN", "Ошибка claims")
 			return
 		}
 
 		userID, ok := claims["user_id"].(float64) // jwt числа — float64
 		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id не найден в токене"})
-			c.Abort()
+			httperr.Abort(c, codes.Unauthenticated, "INVALID_TOKEN", "user_id не найден в токене")
+			return
+		}
+
+		jti, ok := claims["jti"].(string)
+		if !ok || jti == "" {
+			httperr.Abort(c, codes.Unauthenticated, "INVALID_TOKEN", "jti не найден в токене")
+			return
+		}
+
+		// токены без sid выпущены до появления сессий и скоро истекут
+		sid, _ := claims["sid"].(string)
+
+		isRevoked, err := revoked.IsRevoked(c.Request.Context(), jti)
+		if err == nil && !isRevoked && sid != "" {
+			isRevoked, err = revoked.IsRevoked(c.Request.Context(), revocation.SessionKey(sid))
+		}
+		if err != nil {
+			httperr.Abort(c, codes.Unavailable, "", "не удалось проверить токен")
+			return
+		}
+		if isRevoked {
+			httperr.Abort(c, codes.Unauthenticated, "TOKEN_REVOKED", "Токен отозван")
 			return
 		}
 
-		// Передаём userID в context
-		c.Set("userID", int(userID))
+		email, _ := claims["email"].(string)
+		exp, _ := claims["exp"].(float64)
+		role, _ := claims["role"].(string)
+
+		setPrincipal(c, &grpcauth.Principal{
+			UserID:      int64(userID),
+			Email:       email,
+			Role:        role,
+			Permissions: grpcauth.StringSlice(claims["permissions"]),
+			TokenID:     jti,
+			ExpiresAt:   int64(exp),
+			SessionID:   sid,
+		})
 		c.Next()
 	}
 }
+
+const principalKey = "principal"
+
+// setPrincipal запоминает пользователя из токена целиком для Principal
+// и по полям для обработчиков.
+func setPrincipal(c *gin.Context, p *grpcauth.Principal) {
+	c.Set(principalKey, p)
+	c.Set("userID", int(p.UserID))
+	c.Set("email", p.Email)
+	c.Set("role", p.Role)
+	c.Set("permissions", p.Permissions)
+	c.Set("tokenID", p.TokenID)
+	c.Set("tokenExp", p.ExpiresAt)
+	c.Set("sessionID", p.SessionID)
+	c.Set("personalTokenID", p.PersonalTokenID)
+}
+
+// Principal возвращает копию пользователя, которого проверил AuthMiddleware,
+// с активным пространством из RequireWorkspace. Без AuthMiddleware — nil.
+func Principal(c *gin.Context) *grpcauth.Principal {
+	p, ok := c.Get(principalKey)
+	if !ok {
+		return nil
+	}
+	cp := *p.(*grpcauth.Principal)
+	cp.WorkspaceID = c.GetInt64("workspaceID")
+	return &cp
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/httperr"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/rbac"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+)
+
+// RequirePermission пропускает запрос, только если в токене есть право permission.
+// Ставится после AuthMiddleware.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !rbac.Has(c.GetStringSlice("permissions"), permission) {
+			httperr.Abort(c, codes.PermissionDenied, "PERMISSION_REQUIRED", "недостаточно прав: "+permission)
+			return
+		}
+		c.Next()
+	}
+}
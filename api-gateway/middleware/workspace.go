@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/httperr"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/grpcauth"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+)
+
+// WorkspaceHeader — заголовок, в котором клиент передаёт активное пространство.
+const WorkspaceHeader = "X-Workspace-ID"
+
+// MembershipFunc проверяет, состоит ли пользователь из ctx в пространстве.
+type MembershipFunc func(ctx context.Context, workspaceID int64) (bool, error)
+
+// membershipTTL — сколько помним подтверждённое членство. Исключённый из
+// пространства пользователь теряет доступ не позже, чем через это время.
+const membershipTTL = 30 * time.Second
+
+// RequireWorkspace пропускает запрос, только если в X-Workspace-ID указано
+// пространство, в котором состоит пользователь. Ставится после AuthMiddleware.
+func RequireWorkspace(isMember MembershipFunc) gin.HandlerFunc {
+	cache := &membershipCache{entries: make(map[membershipKey]time.Time)}
+
+	return func(c *gin.Context) {
+		workspaceID, err := strconv.ParseInt(c.GetHeader(WorkspaceHeader), 10, 64)
+		if err != nil || workspaceID <= 0 {
+			httperr.Abort(c, codes.InvalidArgument, "WORKSPACE_REQUIRED", "укажите пространство в заголовке "+WorkspaceHeader)
+			return
+		}
+
+		userID := int64(c.GetInt("userID"))
+		key := membershipKey{userID: userID, workspaceID: workspaceID}
+		if !cache.has(key) {
+			ctx := grpcauth.NewContext(c.Request.Context(), Principal(c))
+			ok, err := isMember(ctx, workspaceID)
+			if err != nil {
+				httperr.Abort(c, codes.Unavailable, "", "не удалось проверить пространство")
+				return
+			}
+			if !ok {
+				httperr.Abort(c, codes.PermissionDenied, "NOT_WORKSPACE_MEMBER", "вы не состоите в этом пространстве")
+				return
+			}
+			cache.add(key)
+		}
+
+		c.Set("workspaceID", workspaceID)
+		c.Next()
+	}
+}
+
+type membershipKey struct {
+	userID, workspaceID int64
+}
+
+// membershipCache хранит только положительные ответы, чтобы не ходить
+// в user-service на каждый запрос к задачам.
+type membershipCache struct {
+	mu      sync.Mutex
+	entries map[membershipKey]time.Time
+}
+
+const maxMembershipEntries = 10000
+
+func (m *membershipCache) has(key membershipKey) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	exp, ok := m.entries[key]
+	return ok && time.Now().Before(exp)
+}
+
+func (m *membershipCache) add(key membershipKey) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	if len(m.entries) >= maxMembershipEntries {
+		for k, exp := range m.entries {
+			if !now.Before(exp) {
+				delete(m.entries, k)
+			}
+		}
+	}
+	if len(m.entries) < maxMembershipEntries {
+		m.entries[key] = now.Add(membershipTTL)
+	}
+}
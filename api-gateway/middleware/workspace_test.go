@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/grpcauth"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireWorkspacePassesPrincipal(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		principal *grpcauth.Principal
+	}{
+		{"session", &grpcauth.Principal{
+			UserID: 7, Email: "a@taqsym.uz", Role: "user", Permissions: []string{"tasks:read"},
+			TokenID: "jti", ExpiresAt: 1700000000, SessionID: "sid",
+		}},
+		{"personal token", &grpcauth.Principal{
+			UserID: 7, Email: "a@taqsym.uz", Role: "user", Permissions: []string{"tasks:read"},
+			PersonalTokenID: 3,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var checked, handled *grpcauth.Principal
+			isMember := func(ctx context.Context, workspaceID int64) (bool, error) {
+				checked, _ = grpcauth.FromContext(ctx)
+				return true, nil
+			}
+
+			r := gin.New()
+			r.GET("/",
+				func(c *gin.Context) { setPrincipal(c, tt.principal) },
+				RequireWorkspace(isMember),
+				func(c *gin.Context) { handled = Principal(c) },
+			)
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(WorkspaceHeader, "42")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body)
+			}
+			if !reflect.DeepEqual(checked, tt.principal) {
+				t.Errorf("membership checked as %+v, want %+v", checked, tt.principal)
+			}
+			want := *tt.principal
+			want.WorkspaceID = 42
+			if !reflect.DeepEqual(handled, &want) {
+				t.Errorf("handler got %+v, want %+v", handled, &want)
+			}
+			if tt.principal.WorkspaceID != 0 {
+				t.Errorf("Principal modified the stored principal")
+			}
+		})
+	}
+}
@@ -1,12 +1,19 @@
 package routes
 
 import (
-	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/grpc_clients"
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/httperr"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/jwks"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/revocation"
 	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 )
 
 type LoginRequest struct {
@@ -15,63 +22,187 @@ type LoginRequest struct {
 }
 
 type RegisterRequest struct {
-	Name     string `json:"name" binding:"required"`
-	Email    string `json:"email" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Name        string `json:"name" binding:"required"`
+	Email       string `json:"email" binding:"required"`
+	Password    string `json:"password" binding:"required"`
+	InviteToken string `json:"invite_token"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
 func LoginHandler(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		httperr.Bind(c, err)
 		return
 	}
 
-	resp, err := grpc_clients.AuthClient.Login(c, &authpb.LoginRequest{
+	var trailer metadata.MD
+	resp, err := grpc_clients.AuthClient.Login(clientContext(c), &authpb.LoginRequest{
 		Email:    req.Email,
 		Password: req.Password,
+	}, grpc.Trailer(&trailer))
+	if err != nil {
+		setRetryAfter(c, trailer)
+		httperr.Respond(c, err)
+		return
+	}
+
+	if resp.MfaRequired {
+		c.JSON(http.StatusOK, gin.H{
+			"mfa_required":    true,
+			"challenge_token": resp.ChallengeToken,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":         resp.Token,
+		"refresh_token": resp.RefreshToken,
+		"expires_in":    resp.ExpiresIn,
+	})
+}
+
+func RefreshHandler(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.Bind(c, err)
+		return
+	}
+
+	resp, err := grpc_clients.AuthClient.Refresh(clientContext(c), &authpb.RefreshRequest{
+		RefreshToken: req.RefreshToken,
 	})
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "неверные учетные данные"})
+		httperr.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": resp.Token})
+	c.JSON(http.StatusOK, gin.H{
+		"token":         resp.Token,
+		"refresh_token": resp.RefreshToken,
+		"expires_in":    resp.ExpiresIn,
+	})
 }
 
 func RegisterHandler(c *gin.Context) {
 	var req RegisterRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		httperr.Bind(c, err)
 		return
 	}
 
 	resp, err := grpc_clients.AuthClient.Register(c, &authpb.RegisterRequest{
-		Name:     req.Name,
-		Email:    req.Email,
-		Password: req.Password,
+		Name:        req.Name,
+		Email:       req.Email,
+		Password:    req.Password,
+		InviteToken: req.InviteToken,
 	})
 
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		httperr.Respond(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"id": resp.Id, "message": resp.Message})
 }
 
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// LogoutHandler отзывает текущий access token и, если передан, refresh token.
+// Токен сразу попадает и в локальный список отозванных этого gateway.
+func LogoutHandler(revoked revocation.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req LogoutRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				httperr.Bind(c, err)
+				return
+			}
+		}
+
+		resp, err := grpc_clients.AuthClient.Logout(userContext(c), &authpb.LogoutRequest{
+			RefreshToken: req.RefreshToken,
+		})
+		if err != nil {
+			httperr.Respond(c, err)
+			return
+		}
+
+		exp := time.Unix(c.GetInt64("tokenExp"), 0)
+		if err := revoked.Revoke(c.Request.Context(), c.GetString("tokenID"), exp); err != nil {
+			httperr.Abort(c, codes.Internal, "", "не удалось отозвать токен")
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": resp.Message})
+	}
+}
+
 func ProfileHandler(c *gin.Context) {
 	userID := c.Value("userID").(int)
-	fmt.Println(userID)
-	resp, err := grpc_clients.AuthClient.Profile(c, &authpb.ProfileRequest{
+	resp, err := grpc_clients.AuthClient.Profile(userContext(c), &authpb.ProfileRequest{
 		Id: int64(userID),
 	})
 
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		httperr.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":             resp.Id,
+		"name":           resp.Name,
+		"email":          resp.Email,
+		"role":           resp.Role,
+		"permissions":    resp.Permissions,
+		"email_verified": resp.EmailVerified,
+	})
+}
+
+// JWKSHandler отдаёт публичные ключи проверки токенов (RFC 7517).
+func JWKSHandler(keys *jwks.Remote) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		set := keys.JWKs()
+		if set == nil {
+			httperr.Abort(c, codes.Unavailable, "", "ключи ещё не загружены")
+			return
+		}
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, gin.H{"keys": set})
+	}
+}
+
+type SetUserRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+func SetUserRoleHandler(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || userID <= 0 {
+		httperr.InvalidField(c, "id", "неверный id пользователя")
+		return
+	}
+
+	var req SetUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.Bind(c, err)
+		return
+	}
+
+	resp, err := grpc_clients.AuthClient.SetUserRole(userContext(c), &authpb.SetUserRoleRequest{
+		UserId: userID,
+		Role:   req.Role,
+	})
+	if err != nil {
+		httperr.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"id": resp.Id, "name": resp.Name, "email": resp.Email})
+	c.JSON(http.StatusOK, gin.H{"message": resp.Message})
 }
@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"context"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/middleware"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/grpcauth"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/metadata"
+)
+
+// userContext кладёт пользователя из токена и активное пространство в контекст вызова,
+// grpc_clients подписывает его и передаёт сервисам.
+func userContext(c *gin.Context) context.Context {
+	p := middleware.Principal(c)
+	if p == nil {
+		return c.Request.Context()
+	}
+	return grpcauth.NewContext(c.Request.Context(), p)
+}
+
+// clientContext передаёт сервису адрес клиента, например для ограничения
+// перебора паролей, и его User-Agent для списка сессий.
+func clientContext(c *gin.Context) context.Context {
+	return withClient(c, c.Request.Context())
+}
+
+// withClient добавляет к ctx адрес и User-Agent клиента.
+// Адрес берётся с учётом доверенных прокси gin.
+func withClient(c *gin.Context, ctx context.Context) context.Context {
+	ctx = grpcauth.WithClientIP(ctx, c.ClientIP())
+	return grpcauth.WithUserAgent(ctx, c.Request.UserAgent())
+}
+
+// setRetryAfter переносит trailer retry-after от сервиса в заголовок Retry-After.
+func setRetryAfter(c *gin.Context, trailer metadata.MD) {
+	if v := trailer.Get("retry-after"); len(v) > 0 {
+		c.Header("Retry-After", v[0])
+	}
+}
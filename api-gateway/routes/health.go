@@ -0,0 +1,92 @@
+package routes
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/grpc_clients"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/revocation"
+	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
+	taskpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/task"
+	"github.com/gin-gonic/gin"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// readyTimeout — сколько ждать ответа health сервиса одной зависимости.
+const readyTimeout = 2 * time.Second
+
+// dependencyHealth — что о зависимости видит клиент. /readyz публичный,
+// поэтому текст ошибки с адресами сервисов остаётся только в логе.
+type dependencyHealth struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+}
+
+// HealthzHandler — liveness: процесс жив и обрабатывает запросы.
+// Зависимости не проверяются, иначе их сбой приводил бы к перезапуску gateway.
+func HealthzHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// ReadyzHandler — readiness: параллельно опрашивает gRPC health сервисов
+// и Redis со списком отозванных токенов и отвечает 503, если хотя бы
+// одна зависимость не SERVING.
+func ReadyzHandler(revoked revocation.Store) gin.HandlerFunc {
+	serving := healthpb.HealthCheckResponse_SERVING.String()
+	checks := map[string]func(ctx context.Context) (string, error){
+		"user-service": func(ctx context.Context) (string, error) {
+			resp, err := grpc_clients.AuthHealth.Check(ctx, &healthpb.HealthCheckRequest{Service: authpb.AuthService_ServiceDesc.ServiceName})
+			return resp.GetStatus().String(), err
+		},
+		"task-service": func(ctx context.Context) (string, error) {
+			resp, err := grpc_clients.TaskHealth.Check(ctx, &healthpb.HealthCheckRequest{Service: taskpb.TaskService_ServiceDesc.ServiceName})
+			return resp.GetStatus().String(), err
+		},
+		"redis": func(ctx context.Context) (string, error) {
+			return serving, revocation.Ping(ctx, revoked)
+		},
+	}
+
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readyTimeout)
+		defer cancel()
+
+		var (
+			mu   sync.Mutex
+			wg   sync.WaitGroup
+			deps = make(map[string]dependencyHealth, len(checks))
+		)
+		for name, check := range checks {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				start := time.Now()
+				st, err := check(ctx)
+				latency := time.Since(start)
+				dep := dependencyHealth{
+					Status:    st,
+					LatencyMS: float64(latency.Microseconds()) / 1000,
+				}
+				if err != nil {
+					dep.Status = "UNAVAILABLE"
+					log.Printf("readyz: %s недоступен (%s): %v", name, latency.Round(time.Millisecond), err)
+				}
+				mu.Lock()
+				deps[name] = dep
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		code, overall := http.StatusOK, "ok"
+		for _, dep := range deps {
+			if dep.Status != serving {
+				code, overall = http.StatusServiceUnavailable, "unavailable"
+			}
+		}
+		c.JSON(code, gin.H{"status": overall, "dependencies": deps})
+	}
+}
@@ -0,0 +1,95 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/grpc_clients"
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/httperr"
+	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type VerifySecondFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// VerifySecondFactorHandler — второй шаг входа для пользователей с 2FA.
+func VerifySecondFactorHandler(c *gin.Context) {
+	var req VerifySecondFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.Bind(c, err)
+		return
+	}
+
+	var trailer metadata.MD
+	resp, err := grpc_clients.AuthClient.VerifySecondFactor(clientContext(c), &authpb.VerifySecondFactorRequest{
+		ChallengeToken: req.ChallengeToken,
+		Code:           req.Code,
+	}, grpc.Trailer(&trailer))
+	if err != nil {
+		setRetryAfter(c, trailer)
+		httperr.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":         resp.Token,
+		"refresh_token": resp.RefreshToken,
+		"expires_in":    resp.ExpiresIn,
+	})
+}
+
+func EnrollTOTPHandler(c *gin.Context) {
+	resp, err := grpc_clients.AuthClient.EnrollTOTP(userContext(c), &authpb.EnrollTOTPRequest{})
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"secret": resp.Secret, "otpauth_uri": resp.OtpauthUri})
+}
+
+type TOTPCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+func ConfirmTOTPHandler(c *gin.Context) {
+	var req TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.Bind(c, err)
+		return
+	}
+
+	var trailer metadata.MD
+	ctx := withClient(c, userContext(c))
+	resp, err := grpc_clients.AuthClient.ConfirmTOTP(ctx, &authpb.ConfirmTOTPRequest{Code: req.Code}, grpc.Trailer(&trailer))
+	if err != nil {
+		setRetryAfter(c, trailer)
+		httperr.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": resp.RecoveryCodes})
+}
+
+func DisableTOTPHandler(c *gin.Context) {
+	var req TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.Bind(c, err)
+		return
+	}
+
+	var trailer metadata.MD
+	ctx := withClient(c, userContext(c))
+	resp, err := grpc_clients.AuthClient.DisableTOTP(ctx, &authpb.DisableTOTPRequest{Code: req.Code}, grpc.Trailer(&trailer))
+	if err != nil {
+		setRetryAfter(c, trailer)
+		httperr.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": resp.Message})
+}
@@ -0,0 +1,56 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/grpc_clients"
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/httperr"
+	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
+	"github.com/gin-gonic/gin"
+)
+
+type RequestPasswordResetRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+func RequestPasswordResetHandler(c *gin.Context) {
+	var req RequestPasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.Bind(c, err)
+		return
+	}
+
+	resp, err := grpc_clients.AuthClient.RequestPasswordReset(c, &authpb.RequestPasswordResetRequest{
+		Email: req.Email,
+	})
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": resp.Message})
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+func ResetPasswordHandler(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.Bind(c, err)
+		return
+	}
+
+	resp, err := grpc_clients.AuthClient.ResetPassword(c, &authpb.ResetPasswordRequest{
+		Token:       req.Token,
+		NewPassword: req.Password,
+	})
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": resp.Message})
+}
@@ -0,0 +1,130 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/grpc_clients"
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/httperr"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/revocation"
+	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// UpdateProfileRequest — частичное обновление: отсутствующие поля не меняются.
+type UpdateProfileRequest struct {
+	Name  *string `json:"name"`
+	Email *string `json:"email"`
+}
+
+func UpdateProfileHandler(c *gin.Context) {
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.Bind(c, err)
+		return
+	}
+
+	grpcReq := &authpb.UpdateProfileRequest{UpdateMask: &fieldmaskpb.FieldMask{}}
+	if req.Name != nil {
+		grpcReq.Name = *req.Name
+		grpcReq.UpdateMask.Paths = append(grpcReq.UpdateMask.Paths, "name")
+	}
+	if req.Email != nil {
+		grpcReq.Email = *req.Email
+		grpcReq.UpdateMask.Paths = append(grpcReq.UpdateMask.Paths, "email")
+	}
+	if len(grpcReq.UpdateMask.Paths) == 0 {
+		httperr.Abort(c, codes.InvalidArgument, "EMPTY_UPDATE", "нет полей для обновления")
+		return
+	}
+
+	resp, err := grpc_clients.AuthClient.UpdateProfile(userContext(c), grpcReq)
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":             resp.Id,
+		"name":           resp.Name,
+		"email":          resp.Email,
+		"role":           resp.Role,
+		"permissions":    resp.Permissions,
+		"email_verified": resp.EmailVerified,
+	})
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// ChangePasswordHandler меняет пароль. Остальные сессии завершаются,
+// а клиент получает токены новой сессии вместо текущих.
+func ChangePasswordHandler(c *gin.Context) {
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.Bind(c, err)
+		return
+	}
+
+	var trailer metadata.MD
+	ctx := withClient(c, userContext(c))
+	resp, err := grpc_clients.AuthClient.ChangePassword(ctx, &authpb.ChangePasswordRequest{
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+	}, grpc.Trailer(&trailer))
+	if err != nil {
+		setRetryAfter(c, trailer)
+		httperr.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":         resp.Token,
+		"refresh_token": resp.RefreshToken,
+		"expires_in":    resp.ExpiresIn,
+	})
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// DeleteAccountHandler удаляет аккаунт текущего пользователя. Как и при выходе,
+// токен сразу попадает в локальный список отозванных этого gateway.
+func DeleteAccountHandler(revoked revocation.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req DeleteAccountRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			httperr.Bind(c, err)
+			return
+		}
+
+		var trailer metadata.MD
+		ctx := withClient(c, userContext(c))
+		resp, err := grpc_clients.AuthClient.DeleteAccount(ctx, &authpb.DeleteAccountRequest{
+			Password: req.Password,
+		}, grpc.Trailer(&trailer))
+		if err != nil {
+			setRetryAfter(c, trailer)
+			httperr.Respond(c, err)
+			return
+		}
+
+		exp := time.Unix(c.GetInt64("tokenExp"), 0)
+		if err := revoked.Revoke(c.Request.Context(), c.GetString("tokenID"), exp); err != nil {
+			httperr.Abort(c, codes.Internal, "", "не удалось отозвать токен")
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":     resp.Message,
+			"purge_after": resp.PurgeAfter.AsTime(),
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/grpc_clients"
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/httperr"
+	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
+	"github.com/gin-gonic/gin"
+)
+
+func ListSessionsHandler(c *gin.Context) {
+	resp, err := grpc_clients.AuthClient.ListSessions(userContext(c), &authpb.ListSessionsRequest{})
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	sessions := resp.Sessions
+	if sessions == nil {
+		sessions = []*authpb.Session{}
+	}
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+// RevokeSessionHandler завершает сессию. Её access token отзываются в общем
+// хранилище и перестают приниматься через несколько секунд (кеш revocation).
+func RevokeSessionHandler(c *gin.Context) {
+	resp, err := grpc_clients.AuthClient.RevokeSession(userContext(c), &authpb.RevokeSessionRequest{
+		Id: c.Param("id"),
+	})
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": resp.Message})
+}
@@ -2,44 +2,322 @@ package routes
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/grpc_clients"
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/httperr"
+	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
 	taskpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/task"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type CreateTaskRequest struct {
 	Title       string `json:"title" binding:"required"`
 	Description string `json:"description" binding:"required"`
-	UserID      int64  `json:"user_id" binding:"required"`
+}
+
+// UpdateTaskRequest — PATCH: обновляются только переданные поля.
+type UpdateTaskRequest struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+}
+
+type TransitionTaskRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+type AssignTaskRequest struct {
+	AssigneeID int64 `json:"assignee_id"`
+	// round_robin, least_loaded или weighted — если assignee_id не указан
+	Strategy string `json:"strategy"`
+	Team     []struct {
+		UserID   int64 `json:"user_id" binding:"required"`
+		Capacity int32 `json:"capacity"`
+	} `json:"team"`
+}
+
+var distributionStrategies = map[string]taskpb.DistributionStrategy{
+	"round_robin":  taskpb.DistributionStrategy_DISTRIBUTION_STRATEGY_ROUND_ROBIN,
+	"least_loaded": taskpb.DistributionStrategy_DISTRIBUTION_STRATEGY_LEAST_LOADED,
+	"weighted":     taskpb.DistributionStrategy_DISTRIBUTION_STRATEGY_WEIGHTED,
+}
+
+// TasksListQuery — параметры GET /tasks.
+// status и expand можно передать несколько раз или через запятую.
+type TasksListQuery struct {
+	AuthorID      int64     `form:"author_id"`
+	AssigneeID    int64     `form:"assignee_id"`
+	Status        []string  `form:"status"`
+	CreatedAfter  time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	Query         string    `form:"q"`
+	Sort          string    `form:"sort"`
+	PageSize      int32     `form:"page_size"`
+	PageToken     string    `form:"page_token"`
+	// Expand — какие связанные объекты встроить в задачи: author, assignee.
+	Expand []string `form:"expand"`
+}
+
+// taskView — задача со встроенными по ?expand пользователями.
+type taskView struct {
+	*taskpb.Task
+	Author   *authpb.User `json:"author,omitempty"`
+	Assignee *authpb.User `json:"assignee,omitempty"`
 }
 
 func TasksListHandler(c *gin.Context) {
-	resp, err := grpc_clients.TaskClient.ListTasks(c, &taskpb.ListTasksRequest{})
+	var q TasksListQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		httperr.Bind(c, err)
+		return
+	}
+
+	req := &taskpb.ListTasksRequest{
+		AuthorId:   q.AuthorID,
+		AssigneeId: q.AssigneeID,
+		Query:      q.Query,
+		OrderBy:    q.Sort,
+		PageSize:   q.PageSize,
+		PageToken:  q.PageToken,
+	}
+	req.Statuses = splitList(q.Status)
+
+	var expandAuthor, expandAssignee bool
+	for _, field := range splitList(q.Expand) {
+		switch field {
+		case "author":
+			expandAuthor = true
+		case "assignee":
+			expandAssignee = true
+		default:
+			httperr.InvalidField(c, "expand", "неизвестное значение expand: "+field)
+			return
+		}
+	}
+	if !q.CreatedAfter.IsZero() {
+		req.CreatedAfter = timestamppb.New(q.CreatedAfter)
+	}
+	if !q.CreatedBefore.IsZero() {
+		req.CreatedBefore = timestamppb.New(q.CreatedBefore)
+	}
+
+	resp, err := grpc_clients.TaskClient.ListTasks(userContext(c), req)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "неверные учетные данные"})
+		httperr.Respond(c, err)
+		return
+	}
+
+	if !expandAuthor && !expandAssignee {
+		c.JSON(http.StatusOK, gin.H{"tasks": resp.Tasks, "next_page_token": resp.NextPageToken})
+		return
+	}
+
+	// всех пользователей страницы запрашиваем одним вызовом
+	var ids []int64
+	for _, t := range resp.Tasks {
+		if expandAuthor {
+			ids = append(ids, t.UserId)
+		}
+		if expandAssignee {
+			ids = append(ids, t.AssigneeId)
+		}
+	}
+	users := newUserLoader(userContext(c))
+	if err := users.Load(ids...); err != nil {
+		httperr.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": resp.Tasks})
+	tasks := make([]taskView, 0, len(resp.Tasks))
+	for _, t := range resp.Tasks {
+		v := taskView{Task: t}
+		if expandAuthor {
+			v.Author = users.Get(t.UserId)
+		}
+		if expandAssignee {
+			v.Assignee = users.Get(t.AssigneeId)
+		}
+		tasks = append(tasks, v)
+	}
+	c.JSON(http.StatusOK, gin.H{"tasks": tasks, "next_page_token": resp.NextPageToken})
+}
+
+// splitList разбирает параметр, переданный несколько раз или через запятую.
+func splitList(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
 }
 
 func CreateTask(c *gin.Context) {
 	var req CreateTaskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		httperr.Bind(c, err)
 		return
 	}
 
-	resp, err := grpc_clients.TaskClient.Create(c, &taskpb.CreateTaskRequest{
+	resp, err := grpc_clients.TaskClient.Create(userContext(c), &taskpb.CreateTaskRequest{
 		Title:       req.Title,
 		Description: req.Description,
-		UserId:      req.UserID,
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "не удалось создать задачу"})
+		httperr.Respond(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{"id": resp.Task})
 }
+
+func GetTaskHandler(c *gin.Context) {
+	id, ok := taskIDParam(c)
+	if !ok {
+		return
+	}
+
+	resp, err := grpc_clients.TaskClient.GetTask(userContext(c), &taskpb.GetTaskRequest{
+		Id: id,
+	})
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"task": resp.Task})
+}
+
+func UpdateTaskHandler(c *gin.Context) {
+	id, ok := taskIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.Bind(c, err)
+		return
+	}
+
+	grpcReq := &taskpb.UpdateTaskRequest{
+		Id:         id,
+		UpdateMask: &fieldmaskpb.FieldMask{},
+	}
+	if req.Title != nil {
+		grpcReq.Title = *req.Title
+		grpcReq.UpdateMask.Paths = append(grpcReq.UpdateMask.Paths, "title")
+	}
+	if req.Description != nil {
+		grpcReq.Description = *req.Description
+		grpcReq.UpdateMask.Paths = append(grpcReq.UpdateMask.Paths, "description")
+	}
+	if len(grpcReq.UpdateMask.Paths) == 0 {
+		httperr.Abort(c, codes.InvalidArgument, "EMPTY_UPDATE", "нет полей для обновления")
+		return
+	}
+
+	resp, err := grpc_clients.TaskClient.UpdateTask(userContext(c), grpcReq)
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"task": resp.Task})
+}
+
+func DeleteTaskHandler(c *gin.Context) {
+	id, ok := taskIDParam(c)
+	if !ok {
+		return
+	}
+
+	resp, err := grpc_clients.TaskClient.DeleteTask(userContext(c), &taskpb.DeleteTaskRequest{
+		Id: id,
+	})
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": resp.Message})
+}
+
+func TransitionTaskHandler(c *gin.Context) {
+	id, ok := taskIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req TransitionTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.Bind(c, err)
+		return
+	}
+
+	resp, err := grpc_clients.TaskClient.TransitionTask(userContext(c), &taskpb.TransitionTaskRequest{
+		Id:     id,
+		Status: req.Status,
+	})
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"task": resp.Task})
+}
+
+func AssignTaskHandler(c *gin.Context) {
+	id, ok := taskIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req AssignTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.Bind(c, err)
+		return
+	}
+
+	grpcReq := &taskpb.AssignTaskRequest{
+		Id:         id,
+		AssigneeId: req.AssigneeID,
+	}
+	// членство исполнителей проверяет task-service
+	if req.AssigneeID == 0 {
+		strategy, ok := distributionStrategies[req.Strategy]
+		if !ok {
+			httperr.InvalidField(c, "strategy", "укажите assignee_id или strategy")
+			return
+		}
+		grpcReq.Strategy = strategy
+		for _, m := range req.Team {
+			grpcReq.Team = append(grpcReq.Team, &taskpb.TeamMember{UserId: m.UserID, Capacity: m.Capacity})
+		}
+	}
+
+	resp, err := grpc_clients.TaskClient.AssignTask(userContext(c), grpcReq)
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"task": resp.Task})
+}
+
+func taskIDParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		httperr.InvalidField(c, "id", "неверный id задачи")
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,72 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/grpc_clients"
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/httperr"
+	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type CreatePersonalTokenRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Scopes []string `json:"scopes" binding:"required"`
+	// ExpiresAt не задан — токен бессрочный.
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func CreatePersonalTokenHandler(c *gin.Context) {
+	var req CreatePersonalTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.Bind(c, err)
+		return
+	}
+
+	grpcReq := &authpb.CreatePersonalTokenRequest{Name: req.Name, Scopes: req.Scopes}
+	if req.ExpiresAt != nil {
+		grpcReq.ExpiresAt = timestamppb.New(*req.ExpiresAt)
+	}
+
+	resp, err := grpc_clients.AuthClient.CreatePersonalToken(userContext(c), grpcReq)
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	// токен показывается один раз, восстановить его нельзя
+	c.JSON(http.StatusCreated, gin.H{"personal_token": resp.PersonalToken, "token": resp.Token})
+}
+
+func ListPersonalTokensHandler(c *gin.Context) {
+	resp, err := grpc_clients.AuthClient.ListPersonalTokens(userContext(c), &authpb.ListPersonalTokensRequest{})
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	tokens := resp.Tokens
+	if tokens == nil {
+		tokens = []*authpb.PersonalToken{}
+	}
+	c.JSON(http.StatusOK, gin.H{"tokens": tokens})
+}
+
+func RevokePersonalTokenHandler(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		httperr.InvalidField(c, "id", "неверный id токена")
+		return
+	}
+
+	resp, err := grpc_clients.AuthClient.RevokePersonalToken(userContext(c), &authpb.RevokePersonalTokenRequest{Id: id})
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": resp.Message})
+}
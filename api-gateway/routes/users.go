@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"context"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/grpc_clients"
+	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
+)
+
+// userLoader собирает пользователей для одного HTTP запроса. Повторные id
+// не запрашиваются, а все новые уходят в user-service одним вызовом GetUsers.
+type userLoader struct {
+	ctx   context.Context
+	users map[int64]*authpb.User
+}
+
+func newUserLoader(ctx context.Context) *userLoader {
+	return &userLoader{ctx: ctx, users: map[int64]*authpb.User{}}
+}
+
+// Load загружает ещё не загруженных пользователей из ids.
+func (l *userLoader) Load(ids ...int64) error {
+	var missing []int64
+	for _, id := range ids {
+		if _, ok := l.users[id]; ok || id <= 0 {
+			continue
+		}
+		// пользователь, которого не вернут, тоже считается загруженным
+		l.users[id] = nil
+		missing = append(missing, id)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	resp, err := grpc_clients.AuthClient.GetUsers(l.ctx, &authpb.GetUsersRequest{Ids: missing})
+	if err != nil {
+		for _, id := range missing {
+			delete(l.users, id)
+		}
+		return err
+	}
+	for _, u := range resp.Users {
+		l.users[u.Id] = u
+	}
+	return nil
+}
+
+// Get возвращает загруженного пользователя или nil, если его нет или он не виден.
+func (l *userLoader) Get(id int64) *authpb.User {
+	return l.users[id]
+}
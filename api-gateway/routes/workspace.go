@@ -0,0 +1,163 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/grpc_clients"
+	"github.com/Murodkadirkhanoff/taqsym.uz/api-gateway/httperr"
+	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
+	"github.com/gin-gonic/gin"
+)
+
+type CreateWorkspaceRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+func CreateWorkspaceHandler(c *gin.Context) {
+	var req CreateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.Bind(c, err)
+		return
+	}
+
+	resp, err := grpc_clients.AuthClient.CreateWorkspace(userContext(c), &authpb.CreateWorkspaceRequest{
+		Name: req.Name,
+	})
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"workspace": resp.Workspace})
+}
+
+func ListWorkspacesHandler(c *gin.Context) {
+	resp, err := grpc_clients.AuthClient.ListWorkspaces(userContext(c), &authpb.ListWorkspacesRequest{})
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	workspaces := resp.Workspaces
+	if workspaces == nil {
+		workspaces = []*authpb.Workspace{}
+	}
+	c.JSON(http.StatusOK, gin.H{"workspaces": workspaces})
+}
+
+func WorkspaceMembersHandler(c *gin.Context) {
+	id, ok := workspaceIDParam(c)
+	if !ok {
+		return
+	}
+
+	resp, err := grpc_clients.AuthClient.ListWorkspaceMembers(userContext(c), &authpb.ListWorkspaceMembersRequest{
+		WorkspaceId: id,
+	})
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": resp.Members})
+}
+
+type InviteMemberRequest struct {
+	Email string `json:"email" binding:"required"`
+	Role  string `json:"role"`
+}
+
+func InviteMemberHandler(c *gin.Context) {
+	id, ok := workspaceIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.Bind(c, err)
+		return
+	}
+
+	resp, err := grpc_clients.AuthClient.InviteMember(userContext(c), &authpb.InviteMemberRequest{
+		WorkspaceId: id,
+		Email:       req.Email,
+		Role:        req.Role,
+	})
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	// токен показывается один раз: его нужно передать приглашённому
+	c.JSON(http.StatusCreated, gin.H{"invitation": resp.Invitation, "token": resp.Token})
+}
+
+func ListInvitationsHandler(c *gin.Context) {
+	id, ok := workspaceIDParam(c)
+	if !ok {
+		return
+	}
+
+	resp, err := grpc_clients.AuthClient.ListInvitations(userContext(c), &authpb.ListInvitationsRequest{
+		WorkspaceId: id,
+	})
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	invitations := resp.Invitations
+	if invitations == nil {
+		invitations = []*authpb.Invitation{}
+	}
+	c.JSON(http.StatusOK, gin.H{"invitations": invitations})
+}
+
+func RevokeInvitationHandler(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		httperr.InvalidField(c, "id", "неверный id приглашения")
+		return
+	}
+
+	resp, err := grpc_clients.AuthClient.RevokeInvitation(userContext(c), &authpb.RevokeInvitationRequest{Id: id})
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": resp.Message})
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+func AcceptInvitationHandler(c *gin.Context) {
+	var req AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.Bind(c, err)
+		return
+	}
+
+	resp, err := grpc_clients.AuthClient.AcceptInvitation(userContext(c), &authpb.AcceptInvitationRequest{
+		Token: req.Token,
+	})
+	if err != nil {
+		httperr.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"workspace_id": resp.WorkspaceId, "role": resp.Role})
+}
+
+func workspaceIDParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		httperr.InvalidField(c, "id", "неверный id пространства")
+		return 0, false
+	}
+	return id, true
+}
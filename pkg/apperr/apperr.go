@@ -0,0 +1,89 @@
+// Package apperr — типизированные ошибки предметной области. Сервисы
+// объявляют через него свои ошибки, а ToStatus единообразно переводит их
+// в gRPC статус с деталями, по которым gateway строит ответ клиенту.
+package apperr
+
+import "errors"
+
+// Kind — вид ошибки, от него зависят gRPC и HTTP коды.
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindInvalidArgument
+	KindNotFound
+	KindAlreadyExists
+	KindPermissionDenied
+	KindUnauthenticated
+	// KindConflict — операция невозможна в текущем состоянии объекта.
+	KindConflict
+	KindResourceExhausted
+)
+
+// Error — ошибка предметной области. Объявляется один раз как sentinel
+// и сравнивается через errors.Is; обёртки fmt.Errorf("%w: ...") сохраняют
+// вид и код, а текст ответа берётся у обёртки.
+type Error struct {
+	Kind Kind
+	// Reason — машиночитаемый код, например EMAIL_TAKEN.
+	Reason  string
+	Message string
+	// Field — поле запроса, к которому относится ошибка; для BadRequest.
+	Field string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func InvalidArgument(reason, message string) *Error {
+	return &Error{Kind: KindInvalidArgument, Reason: reason, Message: message}
+}
+
+// InvalidField — неверное значение конкретного поля запроса.
+func InvalidField(field, reason, message string) *Error {
+	return &Error{Kind: KindInvalidArgument, Reason: reason, Message: message, Field: field}
+}
+
+// Required — обязательное поле не заполнено.
+func Required(field string) *Error {
+	return InvalidField(field, "REQUIRED", field+" is required")
+}
+
+func NotFound(reason, message string) *Error {
+	return &Error{Kind: KindNotFound, Reason: reason, Message: message}
+}
+
+func AlreadyExists(reason, message string) *Error {
+	return &Error{Kind: KindAlreadyExists, Reason: reason, Message: message}
+}
+
+func PermissionDenied(reason, message string) *Error {
+	return &Error{Kind: KindPermissionDenied, Reason: reason, Message: message}
+}
+
+func Unauthenticated(reason, message string) *Error {
+	return &Error{Kind: KindUnauthenticated, Reason: reason, Message: message}
+}
+
+func Conflict(reason, message string) *Error {
+	return &Error{Kind: KindConflict, Reason: reason, Message: message}
+}
+
+func ResourceExhausted(reason, message string) *Error {
+	return &Error{Kind: KindResourceExhausted, Reason: reason, Message: message}
+}
+
+// WithKind возвращает ту же ошибку с другим видом — когда смысл зависит от
+// вызова: неверный токен из ссылки в письме — это неверный аргумент, а не
+// отсутствие входа. Ошибки не из apperr возвращаются как есть.
+func WithKind(err error, kind Kind) error {
+	var e *Error
+	if !errors.As(err, &e) {
+		return err
+	}
+	c := *e
+	c.Kind = kind
+	c.Message = err.Error()
+	return &c
+}
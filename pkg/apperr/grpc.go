@@ -0,0 +1,56 @@
+package apperr
+
+import (
+	"errors"
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// Domain — домен в google.rpc.ErrorInfo у всех ошибок сервисов.
+const Domain = "taqsym.uz"
+
+var grpcCodes = map[Kind]codes.Code{
+	KindInternal:          codes.Internal,
+	KindInvalidArgument:   codes.InvalidArgument,
+	KindNotFound:          codes.NotFound,
+	KindAlreadyExists:     codes.AlreadyExists,
+	KindPermissionDenied:  codes.PermissionDenied,
+	KindUnauthenticated:   codes.Unauthenticated,
+	KindConflict:          codes.FailedPrecondition,
+	KindResourceExhausted: codes.ResourceExhausted,
+}
+
+// ToStatus переводит ошибку в gRPC статус. У ошибок apperr в детали
+// попадают ErrorInfo с Reason и, если задано поле, BadRequest. Готовые
+// статусы возвращаются как есть. Остальные ошибки — внутренние: текст
+// пишется в лог, а клиент получает Internal без подробностей.
+func ToStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var e *Error
+	if !errors.As(err, &e) {
+		log.Printf("внутренняя ошибка: %v", err)
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	st := status.New(grpcCodes[e.Kind], err.Error())
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: e.Reason, Domain: Domain}}
+	if e.Field != "" {
+		details = append(details, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: e.Field, Description: err.Error()}},
+		})
+	}
+	if detailed, derr := st.WithDetails(details...); derr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
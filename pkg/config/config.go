@@ -0,0 +1,196 @@
+// Package config загружает настройки сервиса. Источники по возрастанию
+// приоритета: значения по умолчанию из тегов, YAML файл (--config или
+// CONFIG_FILE), переменные окружения и флаги командной строки.
+//
+// Настройки описываются структурой с тегами:
+//
+//	type Config struct {
+//		Database config.Postgres `mapstructure:"database"`
+//		Addr     string          `mapstructure:"addr" default:":50051" usage:"адрес gRPC сервера"`
+//		Key      string          `mapstructure:"key" validate:"required"`
+//	}
+//
+// Ключу database.host соответствуют переменная DATABASE_HOST и флаг --database.host.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Validator — дополнительная проверка загруженных настроек. Load вызывает
+// Validate у самой структуры и у всех вложенных.
+type Validator interface {
+	Validate() error
+}
+
+// field — одна настройка из структуры.
+type field struct {
+	key      string
+	def      string
+	usage    string
+	required bool
+	typ      reflect.Type
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Load заполняет cfg (указатель на структуру) и проверяет его. args —
+// аргументы командной строки без имени программы, обычно os.Args[1:].
+func Load(name string, cfg any, args []string) error {
+	rv := reflect.ValueOf(cfg)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config: %T is not a pointer to struct", cfg)
+	}
+	fields, err := collect(rv.Elem().Type(), "")
+	if err != nil {
+		return err
+	}
+
+	v := viper.New()
+	fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "путь к YAML файлу настроек")
+	for _, f := range fields {
+		if err := defineFlag(fs, f); err != nil {
+			return err
+		}
+		v.SetDefault(f.key, f.def)
+	}
+	if err := fs.Parse(args); err != nil {
+		// --help уже напечатал список флагов, как и flag.ExitOnError
+		if errors.Is(err, pflag.ErrHelp) {
+			os.Exit(0)
+		}
+		return fmt.Errorf("config: %w", err)
+	}
+	if err := v.BindPFlags(fs); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	if *configFile != "" {
+		v.SetConfigFile(*configFile)
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("config: read %s: %w", *configFile, err)
+		}
+	}
+
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	if err := v.Unmarshal(cfg); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	var errs []error
+	for _, f := range fields {
+		if f.required && v.GetString(f.key) == "" {
+			errs = append(errs, fmt.Errorf("%s is required (env %s or flag --%s)", f.key, envName(f.key), f.key))
+		}
+	}
+	if len(errs) == 0 {
+		errs = validate(rv.Elem())
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("config: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// collect обходит структуру и возвращает её настройки с полными ключами.
+func collect(t reflect.Type, prefix string) ([]field, error) {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := sf.Tag.Get("mapstructure")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := prefix + name
+
+		if sf.Type.Kind() == reflect.Struct && sf.Type != durationType {
+			nested, err := collect(sf.Type, key+".")
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, nested...)
+			continue
+		}
+
+		switch sf.Type.Kind() {
+		case reflect.String, reflect.Bool, reflect.Int, reflect.Int64:
+		case reflect.Slice:
+			if sf.Type.Elem().Kind() != reflect.String {
+				return nil, fmt.Errorf("config: %s: unsupported type %s", key, sf.Type)
+			}
+		default:
+			return nil, fmt.Errorf("config: %s: unsupported type %s", key, sf.Type)
+		}
+		fields = append(fields, field{
+			key:      key,
+			def:      sf.Tag.Get("default"),
+			usage:    sf.Tag.Get("usage"),
+			required: sf.Tag.Get("validate") == "required",
+			typ:      sf.Type,
+		})
+	}
+	return fields, nil
+}
+
+// validate вызывает Validate у вложенных структур, затем у самой структуры.
+func validate(rv reflect.Value) []error {
+	var errs []error
+	for i := 0; i < rv.NumField(); i++ {
+		if f := rv.Field(i); f.Kind() == reflect.Struct && f.CanInterface() {
+			errs = append(errs, validate(f)...)
+		}
+	}
+	if val, ok := rv.Addr().Interface().(Validator); ok {
+		if err := val.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// defineFlag добавляет флаг --<ключ>. Значение по умолчанию у флага пустое:
+// незаданный флаг не перекрывает файл и окружение.
+func defineFlag(fs *pflag.FlagSet, f field) error {
+	hint := "env " + envName(f.key)
+	if f.def != "" {
+		hint += ", default " + f.def
+	}
+	usage := hint
+	if f.usage != "" {
+		usage = f.usage + " (" + hint + ")"
+	}
+
+	switch {
+	case f.typ == durationType:
+		if f.def != "" {
+			if _, err := time.ParseDuration(f.def); err != nil {
+				return fmt.Errorf("config: %s: bad default %q: %w", f.key, f.def, err)
+			}
+		}
+		fs.Duration(f.key, 0, usage)
+	case f.typ.Kind() == reflect.Bool:
+		fs.Bool(f.key, false, usage)
+	case f.typ.Kind() == reflect.Int, f.typ.Kind() == reflect.Int64:
+		fs.Int64(f.key, 0, usage)
+	case f.typ.Kind() == reflect.Slice:
+		fs.StringSlice(f.key, nil, usage)
+	default:
+		fs.String(f.key, "", usage)
+	}
+	return nil
+}
+
+func envName(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testConfig struct {
+	Database Postgres      `mapstructure:"database"`
+	Addr     string        `mapstructure:"addr" default:":50051"`
+	Debug    bool          `mapstructure:"debug"`
+	Limit    int           `mapstructure:"limit" default:"10"`
+	TTL      time.Duration `mapstructure:"ttl" default:"15m"`
+	Origins  []string      `mapstructure:"origins"`
+	Key      string        `mapstructure:"key" validate:"required"`
+}
+
+func (c *testConfig) Validate() error {
+	if c.Limit <= 0 {
+		return errors.New("limit must be positive")
+	}
+	return nil
+}
+
+// clearEnv убирает переменные, которые могли остаться от окружения разработчика.
+func clearEnv(t *testing.T) {
+	for _, name := range []string{"CONFIG_FILE", "ADDR", "DEBUG", "LIMIT", "TTL", "ORIGINS", "KEY",
+		"DATABASE_HOST", "DATABASE_PORT", "DATABASE_PASSWORD"} {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+}
+
+func writeFile(t *testing.T, body string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	clearEnv(t)
+	var cfg testConfig
+	if err := Load("test", &cfg, []string{"--key", "k", "--database.password", "secret"}); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := testConfig{
+		Database: Postgres{Host: "localhost", Port: 5432, User: "postgres", Password: "secret", Name: "postgres", SSLMode: "disable"},
+		Addr:     ":50051",
+		Limit:    10,
+		TTL:      15 * time.Minute,
+		Origins:  []string{},
+		Key:      "k",
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Load = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeFile(t, `
+addr: ":1000"
+limit: 20
+ttl: 1m
+origins: [a, b]
+key: from-file
+database:
+  host: db
+  password: file-secret
+`)
+
+	tests := []struct {
+		name  string
+		env   map[string]string
+		args  []string
+		check func(t *testing.T, cfg testConfig)
+	}{
+		{
+			name: "file overrides defaults",
+			args: []string{"--config", file},
+			check: func(t *testing.T, cfg testConfig) {
+				if cfg.Addr != ":1000" || cfg.Limit != 20 || cfg.TTL != time.Minute || cfg.Database.Host != "db" {
+					t.Errorf("file values not applied: %+v", cfg)
+				}
+				if !reflect.DeepEqual(cfg.Origins, []string{"a", "b"}) {
+					t.Errorf("origins = %q", cfg.Origins)
+				}
+				if cfg.Database.Port != 5432 {
+					t.Errorf("default database.port lost: %d", cfg.Database.Port)
+				}
+			},
+		},
+		{
+			name: "CONFIG_FILE selects the file",
+			env:  map[string]string{"CONFIG_FILE": file},
+			check: func(t *testing.T, cfg testConfig) {
+				if cfg.Key != "from-file" {
+					t.Errorf("key = %q, want from-file", cfg.Key)
+				}
+			},
+		},
+		{
+			name: "env overrides file",
+			env:  map[string]string{"LIMIT": "30", "DATABASE_HOST": "env-db"},
+			args: []string{"--config", file},
+			check: func(t *testing.T, cfg testConfig) {
+				if cfg.Limit != 30 || cfg.Database.Host != "env-db" {
+					t.Errorf("env values not applied: %+v", cfg)
+				}
+			},
+		},
+		{
+			name: "flags override env",
+			env:  map[string]string{"LIMIT": "30", "DEBUG": "false"},
+			args: []string{"--config", file, "--limit", "40", "--debug", "--origins", "x,y"},
+			check: func(t *testing.T, cfg testConfig) {
+				if cfg.Limit != 40 || !cfg.Debug || !reflect.DeepEqual(cfg.Origins, []string{"x", "y"}) {
+					t.Errorf("flag values not applied: %+v", cfg)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			var cfg testConfig
+			if err := Load("test", &cfg, tt.args); err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestLoadValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr []string
+	}{
+		{"required fields", nil, []string{"key is required (env KEY or flag --key)", "database.password is required"}},
+		{"struct Validate", []string{"--key", "k", "--database.password", "p", "--limit", "-1"}, []string{"limit must be positive"}},
+		{"nested Validate", []string{"--key", "k", "--database.password", "p", "--database.port", "70000"}, []string{"database.port 70000 is out of range"}},
+		{"unknown flag", []string{"--nope"}, []string{"unknown flag: --nope"}},
+		{"bad duration", []string{"--key", "k", "--database.password", "p", "--ttl", "soon"}, []string{"ttl"}},
+		{"missing file", []string{"--config", "/nonexistent/config.yaml"}, []string{"read /nonexistent/config.yaml"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			var cfg testConfig
+			err := Load("test", &cfg, tt.args)
+			if err == nil {
+				t.Fatalf("Load accepted %q", tt.args)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadRejectsUnsupportedTypes(t *testing.T) {
+	var notStruct string
+	if err := Load("test", &notStruct, nil); err == nil {
+		t.Error("Load accepted a pointer to string")
+	}
+	var withMap struct {
+		Limits map[string]int `mapstructure:"limits"`
+	}
+	if err := Load("test", &withMap, nil); err == nil {
+		t.Error("Load accepted a map field")
+	}
+	var badDefault struct {
+		TTL time.Duration `mapstructure:"ttl" default:"forever"`
+	}
+	if err := Load("test", &badDefault, nil); err == nil {
+		t.Error("Load accepted a bad duration default")
+	}
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+)
+
+// Postgres — настройки подключения к PostgreSQL.
+type Postgres struct {
+	Host     string `mapstructure:"host" default:"localhost"`
+	Port     int    `mapstructure:"port" default:"5432"`
+	User     string `mapstructure:"user" default:"postgres"`
+	Password string `mapstructure:"password" validate:"required"`
+	Name     string `mapstructure:"name" default:"postgres"`
+	SSLMode  string `mapstructure:"sslmode" default:"disable"`
+}
+
+// DSN возвращает строку подключения для lib/pq.
+func (p Postgres) DSN() string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(p.User, p.Password),
+		Host:     net.JoinHostPort(p.Host, strconv.Itoa(p.Port)),
+		Path:     "/" + p.Name,
+		RawQuery: url.Values{"sslmode": {p.SSLMode}}.Encode(),
+	}
+	return u.String()
+}
+
+// Validate проверяет, что порт в допустимом диапазоне.
+func (p Postgres) Validate() error {
+	if p.Port <= 0 || p.Port > 65535 {
+		return fmt.Errorf("database.port %d is out of range", p.Port)
+	}
+	return nil
+}
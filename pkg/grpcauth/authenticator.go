@@ -0,0 +1,128 @@
+package grpcauth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/revocation"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	ErrNoCredentials      = errors.New("no credentials in metadata")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrRevoked            = errors.New("token has been revoked")
+)
+
+// Authenticator проверяет метаданные входящего вызова и возвращает principal.
+// Если подходящих учётных данных нет, возвращается ErrNoCredentials.
+type Authenticator interface {
+	Authenticate(ctx context.Context, md metadata.MD) (*Principal, error)
+}
+
+// AuthenticatorFunc позволяет использовать функцию как Authenticator.
+type AuthenticatorFunc func(ctx context.Context, md metadata.MD) (*Principal, error)
+
+func (f AuthenticatorFunc) Authenticate(ctx context.Context, md metadata.MD) (*Principal, error) {
+	return f(ctx, md)
+}
+
+// Chain пробует аутентификаторы по очереди и возвращает первый успешный результат.
+// Переход к следующему происходит только при ErrNoCredentials.
+func Chain(auths ...Authenticator) Authenticator {
+	return AuthenticatorFunc(func(ctx context.Context, md metadata.MD) (*Principal, error) {
+		for _, a := range auths {
+			p, err := a.Authenticate(ctx, md)
+			if errors.Is(err, ErrNoCredentials) {
+				continue
+			}
+			return p, err
+		}
+		return nil, ErrNoCredentials
+	})
+}
+
+// JWT проверяет токен из метаданных "authorization: Bearer <token>".
+// keyfunc возвращает публичный ключ по kid, например jwks.KeySet.Keyfunc.
+func JWT(keyfunc jwt.Keyfunc) Authenticator {
+	return AuthenticatorFunc(func(ctx context.Context, md metadata.MD) (*Principal, error) {
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return nil, ErrNoCredentials
+		}
+
+		parts := strings.SplitN(values[0], " ", 2)
+		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+			return nil, ErrInvalidCredentials
+		}
+
+		claims := jwt.MapClaims{}
+		_, err := jwt.ParseWithClaims(parts[1], claims, keyfunc,
+			jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}))
+		if err != nil {
+			return nil, ErrInvalidCredentials
+		}
+
+		userID, ok := claims["user_id"].(float64)
+		if !ok {
+			return nil, ErrInvalidCredentials
+		}
+		email, _ := claims["email"].(string)
+		jti, _ := claims["jti"].(string)
+		sid, _ := claims["sid"].(string)
+		exp, _ := claims["exp"].(float64)
+		role, _ := claims["role"].(string)
+
+		return &Principal{
+			UserID:      int64(userID),
+			Email:       email,
+			Role:        role,
+			Permissions: StringSlice(claims["permissions"]),
+			TokenID:     jti,
+			ExpiresAt:   int64(exp),
+			SessionID:   sid,
+		}, nil
+	})
+}
+
+// Revocable отклоняет principal, чей токен или сессия отозваны. Токены без jti
+// отозвать нельзя, поэтому они тоже отклоняются.
+func Revocable(auth Authenticator, isRevoked func(ctx context.Context, tokenID string) (bool, error)) Authenticator {
+	return AuthenticatorFunc(func(ctx context.Context, md metadata.MD) (*Principal, error) {
+		p, err := auth.Authenticate(ctx, md)
+		if err != nil {
+			return nil, err
+		}
+		if p.TokenID == "" {
+			return nil, ErrInvalidCredentials
+		}
+		revoked, err := isRevoked(ctx, p.TokenID)
+		if err != nil {
+			return nil, err
+		}
+		if !revoked && p.SessionID != "" {
+			revoked, err = isRevoked(ctx, revocation.SessionKey(p.SessionID))
+			if err != nil {
+				return nil, err
+			}
+		}
+		if revoked {
+			return nil, ErrRevoked
+		}
+		return p, nil
+	})
+}
+
+// StringSlice приводит массив из JWT claims ([]interface{}) к []string.
+func StringSlice(v interface{}) []string {
+	items, _ := v.([]interface{})
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package grpcauth
+
+import (
+	"context"
+	"crypto/hmac"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	clientIPKey          = "x-client-ip"
+	clientIPSignatureKey = "x-client-ip-signature"
+	userAgentKey         = "x-client-user-agent"
+)
+
+type (
+	clientIPCtxKey  struct{}
+	userAgentCtxKey struct{}
+)
+
+// WithClientIP запоминает адрес клиента. UnaryClientInterceptor
+// подписывает его и передаёт сервису вместе с вызовом.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPCtxKey{}, ip)
+}
+
+// ClientIP возвращает адрес клиента: переданный gateway'ем, если подпись
+// верна, иначе адрес соединения. На стороне сервиса нужен UnaryClientIPInterceptor.
+func ClientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPCtxKey{}).(string)
+	return ip
+}
+
+// WithUserAgent запоминает User-Agent клиента, UnaryClientInterceptor
+// передаёт его сервису вместе с вызовом.
+func WithUserAgent(ctx context.Context, userAgent string) context.Context {
+	return context.WithValue(ctx, userAgentCtxKey{}, userAgent)
+}
+
+// UserAgent возвращает User-Agent клиента, переданный gateway'ем.
+// Он только для отображения пользователю, поэтому не подписывается.
+func UserAgent(ctx context.Context) string {
+	ua, _ := ctx.Value(userAgentCtxKey{}).(string)
+	return ua
+}
+
+// UnaryClientIPInterceptor — серверный interceptor, который кладёт в контекст
+// адрес клиента для ClientIP и его User-Agent. Адрес из метаданных принимается
+// только с подписью ключом gateway, иначе его мог бы подменить любой вызывающий.
+func UnaryClientIPInterceptor(key []byte) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx = WithClientIP(ctx, incomingClientIP(ctx, key))
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if ua := md.Get(userAgentKey); len(ua) == 1 {
+				ctx = WithUserAgent(ctx, ua[0])
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+func incomingClientIP(ctx context.Context, key []byte) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ips, signatures := md.Get(clientIPKey), md.Get(clientIPSignatureKey)
+	if len(ips) == 1 && len(signatures) == 1 &&
+		hmac.Equal([]byte(signatures[0]), []byte(sign(key, clientIPKey+":"+ips[0]))) {
+		return ips[0]
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return host
+		}
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// signClientIP возвращает метаданные с адресом клиента, подписанным ключом gateway.
+func signClientIP(key []byte, ip string) metadata.MD {
+	return metadata.Pairs(
+		clientIPKey, ip,
+		clientIPSignatureKey, sign(key, clientIPKey+":"+ip),
+	)
+}
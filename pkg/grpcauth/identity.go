@@ -0,0 +1,103 @@
+package grpcauth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	identityKey          = "x-identity"
+	identitySignatureKey = "x-identity-signature"
+
+	// identityTTL — сколько подписанная личность считается свежей.
+	identityTTL = 5 * time.Minute
+)
+
+// signedIdentity — то, что gateway передаёт сервисам после проверки токена.
+type signedIdentity struct {
+	Principal
+	IssuedAt int64 `json:"iat"`
+}
+
+// SignIdentity возвращает метаданные с principal, подписанным ключом gateway.
+func SignIdentity(key []byte, p *Principal, now time.Time) metadata.MD {
+	payload, _ := json.Marshal(signedIdentity{Principal: *p, IssuedAt: now.Unix()})
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return metadata.Pairs(
+		identityKey, encoded,
+		identitySignatureKey, sign(key, encoded),
+	)
+}
+
+// GatewayIdentity проверяет личность, подписанную SignIdentity.
+func GatewayIdentity(key []byte) Authenticator {
+	return AuthenticatorFunc(func(ctx context.Context, md metadata.MD) (*Principal, error) {
+		encoded := md.Get(identityKey)
+		signature := md.Get(identitySignatureKey)
+		if len(encoded) == 0 {
+			return nil, ErrNoCredentials
+		}
+		if len(encoded) != 1 || len(signature) != 1 {
+			return nil, ErrInvalidCredentials
+		}
+		if !hmac.Equal([]byte(signature[0]), []byte(sign(key, encoded[0]))) {
+			return nil, ErrInvalidCredentials
+		}
+
+		payload, err := base64.RawURLEncoding.DecodeString(encoded[0])
+		if err != nil {
+			return nil, ErrInvalidCredentials
+		}
+		var id signedIdentity
+		if err := json.Unmarshal(payload, &id); err != nil || id.UserID <= 0 {
+			return nil, ErrInvalidCredentials
+		}
+
+		age := time.Since(time.Unix(id.IssuedAt, 0))
+		if age > identityTTL || age < -identityTTL {
+			return nil, ErrInvalidCredentials
+		}
+
+		return &id.Principal, nil
+	})
+}
+
+// UnaryClientInterceptor подписывает principal и адрес клиента (WithClientIP)
+// из контекста вызова и передаёт их сервису вместе с User-Agent (WithUserAgent).
+// Вызовы без них уходят как есть.
+func UnaryClientInterceptor(key []byte) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if p, ok := FromContext(ctx); ok {
+			ctx = appendMD(ctx, SignIdentity(key, p, time.Now()))
+		}
+		if ip := ClientIP(ctx); ip != "" {
+			ctx = appendMD(ctx, signClientIP(key, ip))
+		}
+		if ua := UserAgent(ctx); ua != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, userAgentKey, ua)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func appendMD(ctx context.Context, md metadata.MD) context.Context {
+	for k, values := range md {
+		for _, v := range values {
+			ctx = metadata.AppendToOutgoingContext(ctx, k, v)
+		}
+	}
+	return ctx
+}
+
+func sign(key []byte, payload string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
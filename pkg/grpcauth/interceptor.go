@@ -0,0 +1,84 @@
+package grpcauth
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type options struct {
+	public map[string]bool
+}
+
+type Option func(*options)
+
+// WithPublicMethods разрешает вызывать методы без учётных данных,
+// например "/auth.AuthService/Login". Если учётные данные переданы,
+// они всё равно проверяются и principal попадает в контекст.
+func WithPublicMethods(methods ...string) Option {
+	return func(o *options) {
+		for _, m := range methods {
+			o.public[m] = true
+		}
+	}
+}
+
+// UnaryServerInterceptor аутентифицирует каждый unary вызов.
+func UnaryServerInterceptor(auth Authenticator, opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, auth, o, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor аутентифицирует каждый stream вызов.
+func StreamServerInterceptor(auth Authenticator, opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), auth, o, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authenticate(ctx context.Context, auth Authenticator, o *options, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	p, err := auth.Authenticate(ctx, md)
+	switch {
+	case err == nil:
+		return NewContext(ctx, p), nil
+	case errors.Is(err, ErrNoCredentials) && o.public[method]:
+		return ctx, nil
+	case errors.Is(err, ErrNoCredentials):
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	default:
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{public: map[string]bool{}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *wrappedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,49 @@
+// Package grpcauth — общая аутентификация для gRPC сервисов:
+// серверные interceptor'ы, проверка JWT и подписанной gateway'ем личности.
+package grpcauth
+
+import (
+	"context"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/rbac"
+)
+
+// Principal — аутентифицированный пользователь, от имени которого идёт вызов.
+type Principal struct {
+	UserID      int64    `json:"uid"`
+	Email       string   `json:"email,omitempty"`
+	Role        string   `json:"role,omitempty"`
+	Permissions []string `json:"perms,omitempty"`
+	// TokenID и ExpiresAt — jti и exp access token, по которому вошёл пользователь.
+	TokenID   string `json:"jti,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	// SessionID — сессия (вход), к которой привязан access token.
+	SessionID string `json:"sid,omitempty"`
+	// WorkspaceID — активное пространство, членство в нём проверил gateway.
+	WorkspaceID int64 `json:"wid,omitempty"`
+	// PersonalTokenID — personal access token, по которому вошёл пользователь,
+	// 0 для обычной сессии.
+	PersonalTokenID int64 `json:"pat,omitempty"`
+}
+
+// PersonalTokenPrefix — начало каждого personal access token. По нему gateway
+// отличает их от JWT, а сканеры секретов находят случайно опубликованные токены.
+const PersonalTokenPrefix = "tqs_pat_"
+
+type principalKey struct{}
+
+// NewContext кладёт principal в контекст.
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// Can — есть ли у principal право permission.
+func (p *Principal) Can(permission string) bool {
+	return rbac.Has(p.Permissions, permission)
+}
+
+// FromContext достаёт principal, положенный interceptor'ом или NewContext.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
@@ -0,0 +1,98 @@
+// Package healthcheck публикует состояние сервиса через стандартный
+// gRPC health сервис (grpc.health.v1.Health): NOT_SERVING, пока проверка
+// зависимостей, например ping базы, завершается ошибкой.
+package healthcheck
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Methods — методы health сервиса; их нужно сделать публичными
+// (grpcauth.WithPublicMethods), балансировщики ходят без токена.
+var Methods = []string{
+	healthpb.Health_Check_FullMethodName,
+	healthpb.Health_List_FullMethodName,
+	healthpb.Health_Watch_FullMethodName,
+}
+
+// Check проверяет зависимости сервиса.
+type Check func(ctx context.Context) error
+
+// Reporter периодически выполняет проверку и обновляет статус.
+type Reporter struct {
+	hs       *health.Server
+	check    Check
+	services []string
+	// status — результат последней проверки; до первой UNKNOWN.
+	status healthpb.HealthCheckResponse_ServingStatus
+}
+
+// Register добавляет health сервис в srv. Статус выставляется для всего
+// сервера ("") и для каждого имени из services; до первой проверки
+// сервис считается NOT_SERVING.
+func Register(srv *grpc.Server, check Check, services ...string) *Reporter {
+	r := &Reporter{
+		hs:       health.NewServer(),
+		check:    check,
+		services: append([]string{""}, services...),
+	}
+	r.set(healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(srv, r.hs)
+	return r
+}
+
+// Run проверяет зависимости каждые interval до отмены ctx, после чего
+// переводит все статусы в NOT_SERVING, чтобы балансировщик перестал
+// присылать запросы на время остановки.
+func (r *Reporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		r.probe(ctx, interval)
+
+		select {
+		case <-ctx.Done():
+			r.hs.Shutdown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Reporter) probe(ctx context.Context, timeout time.Duration) {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	status := healthpb.HealthCheckResponse_SERVING
+	err := r.check(checkCtx)
+	if err != nil {
+		if ctx.Err() != nil {
+			// проверку прервала остановка сервиса, а не зависимость
+			return
+		}
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	if status == r.status {
+		return
+	}
+
+	if err != nil {
+		log.Printf("сервис недоступен: %v", err)
+	} else {
+		log.Println("зависимости доступны, сервис готов")
+	}
+	r.status = status
+	r.set(status)
+}
+
+func (r *Reporter) set(status healthpb.HealthCheckResponse_ServingStatus) {
+	for _, s := range r.services {
+		r.hs.SetServingStatus(s, status)
+	}
+}
@@ -0,0 +1,40 @@
+package jwks
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+)
+
+// JWK — публичный ключ в формате RFC 8037 (OKP, Ed25519).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+}
+
+func NewJWK(k *Key) JWK {
+	return JWK{
+		Kty: "OKP",
+		Kid: k.ID,
+		Use: "sig",
+		Alg: "EdDSA",
+		Crv: "Ed25519",
+		X:   base64.RawURLEncoding.EncodeToString(k.Public),
+	}
+}
+
+// Key разбирает JWK обратно в публичный ключ.
+func (j JWK) Key() (*Key, error) {
+	if j.Kty != "OKP" || j.Crv != "Ed25519" {
+		return nil, fmt.Errorf("jwk %q: unsupported key type %s/%s", j.Kid, j.Kty, j.Crv)
+	}
+	x, err := base64.RawURLEncoding.DecodeString(j.X)
+	if err != nil || len(x) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("jwk %q: invalid public key", j.Kid)
+	}
+	return &Key{ID: j.Kid, Public: ed25519.PublicKey(x)}, nil
+}
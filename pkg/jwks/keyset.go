@@ -0,0 +1,156 @@
+// Package jwks — ключи подписи JWT (Ed25519) с kid и их публикация в виде JWKS.
+//
+// Ротация ключей:
+//  1. положить новый ключ <kid>.pem в каталог ключей и перезапустить user-service —
+//     ключ сразу попадает в JWKS, но подписывает всё ещё старый;
+//  2. когда проверяющие обновили JWKS, переключить JWT_SIGNING_KID на новый kid;
+//  3. спустя время жизни access token удалить старый ключ из каталога.
+package jwks
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrUnknownKey = errors.New("unknown signing key")
+	ErrNoKeys     = errors.New("key set is empty")
+)
+
+// Key — один ключ набора. Private пуст у ключей, известных только по JWKS.
+type Key struct {
+	ID      string
+	Public  ed25519.PublicKey
+	Private ed25519.PrivateKey
+}
+
+// KeySet — набор ключей, один из которых подписывает новые токены,
+// а все остальные только проверяют уже выданные.
+type KeySet struct {
+	keys    map[string]*Key
+	signing string
+}
+
+// NewKeySet собирает набор из ключей. signingKID должен быть среди keys
+// и иметь приватную часть.
+func NewKeySet(signingKID string, keys ...*Key) (*KeySet, error) {
+	ks := &KeySet{keys: map[string]*Key{}, signing: signingKID}
+	for _, k := range keys {
+		ks.keys[k.ID] = k
+	}
+	if k, ok := ks.keys[signingKID]; !ok || k.Private == nil {
+		return nil, fmt.Errorf("%w: no private key for kid %q", ErrUnknownKey, signingKID)
+	}
+	return ks, nil
+}
+
+// LoadDir читает приватные ключи Ed25519 в PKCS#8 PEM из файлов <kid>.pem.
+// Если signingKID пуст, подписывает ключ с наибольшим kid.
+func LoadDir(dir, signingKID string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%w: no *.pem files in %s", ErrNoKeys, dir)
+	}
+	sort.Strings(paths)
+
+	keys := make([]*Key, 0, len(paths))
+	for _, path := range paths {
+		k, err := readKey(path)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	if signingKID == "" {
+		signingKID = keys[len(keys)-1].ID
+	}
+	return NewKeySet(signingKID, keys...)
+}
+
+// Generate создаёт набор из одного случайного ключа.
+// Годится только для локальной разработки: после перезапуска все токены станут недействительны.
+func Generate() (*KeySet, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	kid := make([]byte, 8)
+	if _, err := rand.Read(kid); err != nil {
+		return nil, err
+	}
+	k := &Key{ID: "dev-" + hex.EncodeToString(kid), Public: pub, Private: priv}
+	return NewKeySet(k.ID, k)
+}
+
+// Sign подписывает claims ключом подписи и проставляет kid в заголовок.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	k := ks.keys[ks.signing]
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = k.ID
+	return token.SignedString(k.Private)
+}
+
+// Keyfunc находит публичный ключ по kid из заголовка токена.
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	return lookup(ks.keys, token)
+}
+
+// JWKs возвращает публичные части всех ключей набора.
+func (ks *KeySet) JWKs() []JWK {
+	ids := make([]string, 0, len(ks.keys))
+	for id := range ks.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	out := make([]JWK, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, NewJWK(ks.keys[id]))
+	}
+	return out
+}
+
+func lookup(keys map[string]*Key, token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	k, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: kid %q", ErrUnknownKey, kid)
+	}
+	return k.Public, nil
+}
+
+func readKey(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM block", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	priv, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s: not an Ed25519 key", path)
+	}
+
+	kid := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return &Key{ID: kid, Public: priv.Public().(ed25519.PublicKey), Private: priv}, nil
+}
@@ -0,0 +1,98 @@
+package jwks
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRefetch — не чаще скольких раз перезапрашивать ключи при неизвестном kid.
+const minRefetch = 30 * time.Second
+
+// Remote — набор публичных ключей, полученный от издателя токенов.
+// Обновляется периодически и при встрече неизвестного kid.
+type Remote struct {
+	fetch func(ctx context.Context) ([]JWK, error)
+
+	mu          sync.RWMutex
+	keys        map[string]*Key
+	jwks        []JWK
+	attemptedAt time.Time
+}
+
+func NewRemote(fetch func(ctx context.Context) ([]JWK, error)) *Remote {
+	return &Remote{fetch: fetch, keys: map[string]*Key{}}
+}
+
+// Run обновляет ключи каждые interval, пока не отменён ctx.
+func (r *Remote) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Refresh(ctx); err != nil {
+			log.Printf("не удалось обновить JWKS: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Remote) Refresh(ctx context.Context) error {
+	r.mu.Lock()
+	r.attemptedAt = time.Now()
+	r.mu.Unlock()
+
+	jwks, err := r.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]*Key, len(jwks))
+	for _, j := range jwks {
+		k, err := j.Key()
+		if err != nil {
+			return err
+		}
+		keys[k.ID] = k
+	}
+
+	r.mu.Lock()
+	r.keys, r.jwks = keys, jwks
+	r.mu.Unlock()
+	return nil
+}
+
+// Keyfunc проверяет токен по kid, при неизвестном kid один раз перезапрашивает ключи.
+func (r *Remote) Keyfunc(token *jwt.Token) (interface{}, error) {
+	r.mu.RLock()
+	key, err := lookup(r.keys, token)
+	stale := time.Since(r.attemptedAt) > minRefetch
+	r.mu.RUnlock()
+	if err == nil || !stale {
+		return key, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return lookup(r.keys, token)
+}
+
+// JWKs возвращает последние полученные публичные ключи.
+func (r *Remote) JWKs() []JWK {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.jwks
+}
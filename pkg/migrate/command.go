@@ -0,0 +1,85 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Command — подкоманда `<сервис> migrate up|down [N]|status|version`.
+type Command struct {
+	Name string
+	// Steps — сколько миграций откатывает down; по умолчанию одну.
+	Steps int
+}
+
+// ParseCommand разбирает аргументы после слова migrate. Всё после
+// подкоманды (и числа шагов у down) возвращается как rest — это флаги
+// настроек вроде --config.
+func ParseCommand(args []string) (cmd Command, rest []string, err error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return Command{}, nil, fmt.Errorf("migrate: expected up, down [N], status or version")
+	}
+	cmd.Name, rest = args[0], args[1:]
+
+	switch cmd.Name {
+	case "up", "status", "version":
+	case "down":
+		cmd.Steps = 1
+		if len(rest) > 0 && !strings.HasPrefix(rest[0], "-") {
+			if cmd.Steps, err = strconv.Atoi(rest[0]); err != nil || cmd.Steps <= 0 {
+				return Command{}, nil, fmt.Errorf("migrate: down: %q is not a positive number of steps", rest[0])
+			}
+			rest = rest[1:]
+		}
+	default:
+		return Command{}, nil, fmt.Errorf("migrate: unknown command %q", cmd.Name)
+	}
+	return cmd, rest, nil
+}
+
+// Run выполняет команду и печатает результат в w.
+func (c Command) Run(ctx context.Context, m *Migrator, w io.Writer) error {
+	switch c.Name {
+	case "up":
+		n, err := m.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "применено миграций: %d\n", n)
+	case "down":
+		n, err := m.Down(ctx, c.Steps)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "откачено миграций: %d\n", n)
+	case "version":
+		version, dirty, err := m.Version(ctx)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			fmt.Fprintf(w, "%d (dirty)\n", version)
+		} else {
+			fmt.Fprintln(w, version)
+		}
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied"
+			}
+			fmt.Fprintf(tw, "%04d\t%s\t%s\n", s.Version, s.Name, state)
+		}
+		return tw.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+// Package migrate применяет SQL миграции, встроенные в бинарник сервиса.
+//
+// Файлы называются как у golang-migrate: 0001_create_users.up.sql и
+// 0001_create_users.down.sql, текущая версия хранится в той же таблице
+// schema_migrations, поэтому базы, мигрированные контейнером migrate/migrate,
+// подхватываются без изменений.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+var (
+	ErrDirty          = errors.New("migrate: database is dirty")
+	ErrUnknownVersion = errors.New("migrate: database version is not among known migrations")
+)
+
+var fileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration — пара скриптов одной версии.
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status — миграция и признак того, что она применена.
+type Status struct {
+	Migration
+	Applied bool
+}
+
+// Migrator применяет миграции к одной базе. Up и Down держат advisory lock,
+// так что реплики, стартующие одновременно, применяют миграции по очереди.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	lockKey    int64
+}
+
+// New читает миграции из корня fsys. name отличает advisory lock сервиса
+// от других, если несколько сервисов делят одну базу.
+func New(db *sql.DB, fsys fs.FS, name string) (*Migrator, error) {
+	migrations, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	h := fnv.New64a()
+	h.Write([]byte("migrate:" + name))
+	return &Migrator{db: db, migrations: migrations, lockKey: int64(h.Sum64())}, nil
+}
+
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, e := range entries {
+		m := fileName.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("migrate: %s: bad version", e.Name())
+		}
+		body, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		mig := byVersion[version]
+		if mig == nil {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migrate: version %d has two names: %s and %s", version, mig.Name, m[2])
+		}
+		if m[3] == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migrate: %d_%s has no up script", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Version возвращает текущую версию базы; 0 — ни одна миграция не применена.
+func (m *Migrator) Version(ctx context.Context) (version uint64, dirty bool, err error) {
+	if err := m.ensureTable(ctx, m.db); err != nil {
+		return 0, false, err
+	}
+	return m.version(ctx, m.db)
+}
+
+// Status возвращает все известные миграции с признаком применения.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	version, _, err := m.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		statuses = append(statuses, Status{Migration: mig, Applied: mig.Version <= version})
+	}
+	return statuses, nil
+}
+
+// Up применяет все ещё не применённые миграции и возвращает их число.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.locked(ctx, func(conn *sql.Conn, version uint64) error {
+		for _, mig := range m.migrations {
+			if mig.Version <= version {
+				continue
+			}
+			if err := m.apply(ctx, conn, mig.Up, mig.Version); err != nil {
+				return fmt.Errorf("migrate: %d_%s up: %w", mig.Version, mig.Name, err)
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down откатывает steps последних применённых миграций и возвращает их число.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	reverted := 0
+	err := m.locked(ctx, func(conn *sql.Conn, version uint64) error {
+		for ; reverted < steps && version > 0; reverted++ {
+			i := sort.Search(len(m.migrations), func(i int) bool { return m.migrations[i].Version >= version })
+			if i == len(m.migrations) || m.migrations[i].Version != version {
+				return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+			}
+			mig := m.migrations[i]
+			if mig.Down == "" {
+				return fmt.Errorf("migrate: %d_%s has no down script", mig.Version, mig.Name)
+			}
+
+			var prev uint64
+			if i > 0 {
+				prev = m.migrations[i-1].Version
+			}
+			if err := m.apply(ctx, conn, mig.Down, prev); err != nil {
+				return fmt.Errorf("migrate: %d_%s down: %w", mig.Version, mig.Name, err)
+			}
+			version = prev
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// locked выполняет fn на отдельном соединении под advisory lock.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn, version uint64) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", m.lockKey); err != nil {
+		return fmt.Errorf("migrate: lock: %w", err)
+	}
+	// контекст может быть уже отменён, а блокировку нужно снять в любом случае
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", m.lockKey)
+
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	version, dirty, err := m.version(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("%w at version %d: fix the schema by hand and reset schema_migrations", ErrDirty, version)
+	}
+	return fn(conn, version)
+}
+
+// apply выполняет скрипт и записывает новую версию в одной транзакции:
+// упавшая миграция не оставляет базу наполовину изменённой.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, script string, version uint64) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations"); err != nil {
+		return err
+	}
+	if version > 0 {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)", int64(version)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+type execQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func (m *Migrator) ensureTable(ctx context.Context, db execQuerier) error {
+	_, err := db.ExecContext(ctx,
+		"CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)")
+	return err
+}
+
+func (m *Migrator) version(ctx context.Context, db execQuerier) (uint64, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return uint64(version), dirty, nil
+}
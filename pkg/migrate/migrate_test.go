@@ -0,0 +1,125 @@
+package migrate
+
+import (
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	taskmigrations "github.com/Murodkadirkhanoff/taqsym.uz/task-service/migrations"
+	usermigrations "github.com/Murodkadirkhanoff/taqsym.uz/user-service/migrations"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		args     []string
+		want     Command
+		wantRest []string
+		wantErr  bool
+	}{
+		{args: []string{"up"}, want: Command{Name: "up"}, wantRest: []string{}},
+		{args: []string{"up", "--config", "c.yaml"}, want: Command{Name: "up"}, wantRest: []string{"--config", "c.yaml"}},
+		{args: []string{"status"}, want: Command{Name: "status"}, wantRest: []string{}},
+		{args: []string{"version"}, want: Command{Name: "version"}, wantRest: []string{}},
+		{args: []string{"down"}, want: Command{Name: "down", Steps: 1}, wantRest: []string{}},
+		{args: []string{"down", "3"}, want: Command{Name: "down", Steps: 3}, wantRest: []string{}},
+		{args: []string{"down", "2", "--config=c.yaml"}, want: Command{Name: "down", Steps: 2}, wantRest: []string{"--config=c.yaml"}},
+		{args: []string{"down", "--config", "c.yaml"}, want: Command{Name: "down", Steps: 1}, wantRest: []string{"--config", "c.yaml"}},
+		{args: nil, wantErr: true},
+		{args: []string{"--config", "c.yaml"}, wantErr: true},
+		{args: []string{"sideways"}, wantErr: true},
+		{args: []string{"down", "0"}, wantErr: true},
+		{args: []string{"down", "-1"}, want: Command{Name: "down", Steps: 1}, wantRest: []string{"-1"}},
+		{args: []string{"down", "two"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		cmd, rest, err := ParseCommand(tt.args)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseCommand(%q) = %+v, want error", tt.args, cmd)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseCommand(%q): %v", tt.args, err)
+			continue
+		}
+		if cmd != tt.want || !reflect.DeepEqual(rest, tt.wantRest) {
+			t.Errorf("ParseCommand(%q) = %+v, %q, want %+v, %q", tt.args, cmd, rest, tt.want, tt.wantRest)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_role.up.sql":       {Data: []byte("ALTER TABLE users ADD role TEXT;")},
+		"0002_add_role.down.sql":     {Data: []byte("ALTER TABLE users DROP role;")},
+		"0010_seed.up.sql":           {Data: []byte("INSERT INTO roles VALUES ('admin');")},
+		"0001_create_users.up.sql":   {Data: []byte("CREATE TABLE users ();")},
+		"0001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		"migrations.go":              {Data: []byte("package migrations")},
+		"README.md":                  {Data: []byte("not a migration")},
+	}
+	got, err := load(fsys)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	want := []Migration{
+		{Version: 1, Name: "create_users", Up: "CREATE TABLE users ();", Down: "DROP TABLE users;"},
+		{Version: 2, Name: "add_role", Up: "ALTER TABLE users ADD role TEXT;", Down: "ALTER TABLE users DROP role;"},
+		{Version: 10, Name: "seed", Up: "INSERT INTO roles VALUES ('admin');"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("load = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"zero version", fstest.MapFS{
+			"0000_init.up.sql": {Data: []byte("SELECT 1;")},
+		}},
+		{"two names for one version", fstest.MapFS{
+			"0001_create_users.up.sql":    {Data: []byte("SELECT 1;")},
+			"0001_create_people.down.sql": {Data: []byte("SELECT 1;")},
+		}},
+		{"down without up", fstest.MapFS{
+			"0001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := load(tt.fsys); err == nil {
+				t.Errorf("load = %+v, want error", got)
+			}
+		})
+	}
+}
+
+func TestServiceMigrations(t *testing.T) {
+	services := map[string]fs.FS{
+		"user-service": usermigrations.FS,
+		"task-service": taskmigrations.FS,
+	}
+	for name, fsys := range services {
+		t.Run(name, func(t *testing.T) {
+			migrations, err := load(fsys)
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			for i, m := range migrations {
+				// версии идут подряд, у каждой есть откат
+				if m.Version != uint64(i+1) {
+					t.Errorf("%04d_%s: expected version %d", m.Version, m.Name, i+1)
+				}
+				if strings.TrimSpace(m.Down) == "" {
+					t.Errorf("%04d_%s has no down script", m.Version, m.Name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Package ratelimit — счётчики попыток (например, входа)
+// с экспоненциально растущей задержкой и временной блокировкой.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Policy задаёт, как растёт задержка после попыток.
+type Policy struct {
+	// Free — сколько попыток подряд допускается без задержки.
+	Free int
+	// Base — задержка после первой попытки сверх Free, дальше она удваивается.
+	Base time.Duration
+	// Max — предельная задержка, фактически время блокировки.
+	Max time.Duration
+	// Window — через сколько без новых попыток счётчик обнуляется.
+	Window time.Duration
+}
+
+// Delay возвращает задержку после attempts попыток подряд.
+func (p Policy) Delay(attempts int) time.Duration {
+	over := attempts - p.Free
+	if over <= 0 {
+		return 0
+	}
+	d := p.Base
+	for i := 1; i < over && d < p.Max; i++ {
+		d *= 2
+	}
+	return min(d, p.Max)
+}
+
+// Limiter считает попытки по ключу. Попытка учитывается заранее, до проверки
+// пароля или кода: иначе параллельные запросы проходили бы проверку все разом,
+// пока ни одна неудача ещё не записана. Удачную попытку возвращают Refund или Reset.
+type Limiter interface {
+	// Reserve атомарно проверяет блокировку и учитывает попытку. Если ключ
+	// заблокирован, попытка не учитывается и возвращается время ожидания, иначе 0.
+	Reserve(ctx context.Context, key string) (time.Duration, error)
+	// Refund возвращает одну попытку, оказавшуюся удачной, не трогая остальные.
+	Refund(ctx context.Context, key string) error
+	// Reset обнуляет счётчик, например после успешного входа.
+	Reset(ctx context.Context, key string) error
+}
+
+// MemoryLimiter хранит счётчики в памяти процесса. Подходит для одного
+// экземпляра и для локальной разработки.
+type MemoryLimiter struct {
+	policy Policy
+	mu     sync.Mutex
+	keys   map[string]*memoryEntry
+	now    func() time.Time
+}
+
+type memoryEntry struct {
+	attempts    int
+	lastAttempt time.Time
+	lockedUntil time.Time
+}
+
+// maxMemoryKeys — после скольких ключей удаляются устаревшие записи.
+const maxMemoryKeys = 10000
+
+func NewMemoryLimiter(policy Policy) *MemoryLimiter {
+	return &MemoryLimiter{policy: policy, keys: map[string]*memoryEntry{}, now: time.Now}
+}
+
+func (l *MemoryLimiter) Reserve(_ context.Context, key string) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if len(l.keys) >= maxMemoryKeys {
+		l.cleanup(now)
+	}
+
+	e, ok := l.keys[key]
+	if ok && now.Before(e.lockedUntil) {
+		return e.lockedUntil.Sub(now), nil
+	}
+	if !ok || now.Sub(e.lastAttempt) > l.policy.Window {
+		e = &memoryEntry{}
+		l.keys[key] = e
+	}
+	e.attempts++
+	e.lastAttempt = now
+	e.lockedUntil = now.Add(l.policy.Delay(e.attempts))
+	return 0, nil
+}
+
+func (l *MemoryLimiter) Refund(_ context.Context, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.keys[key]
+	if !ok || e.attempts == 0 {
+		return nil
+	}
+	e.attempts--
+	e.lockedUntil = e.lastAttempt.Add(l.policy.Delay(e.attempts))
+	return nil
+}
+
+func (l *MemoryLimiter) Reset(_ context.Context, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.keys, key)
+	return nil
+}
+
+// cleanup удаляет счётчики, которые уже обнулились бы по Window. Вызывается под l.mu.
+func (l *MemoryLimiter) cleanup(now time.Time) {
+	for k, e := range l.keys {
+		if now.Sub(e.lastAttempt) > l.policy.Window && !now.Before(e.lockedUntil) {
+			delete(l.keys, k)
+		}
+	}
+}
+
+// New возвращает счётчики в памяти, если redisAddr пуст, иначе в Redis.
+// prefix разделяет счётчики с разными политиками в одном Redis.
+func New(redisAddr, prefix string, policy Policy) Limiter {
+	if redisAddr == "" {
+		return NewMemoryLimiter(policy)
+	}
+	return NewRedisLimiter(redis.NewClient(&redis.Options{Addr: redisAddr}), prefix, policy)
+}
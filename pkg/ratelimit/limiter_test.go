@@ -0,0 +1,155 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+var testPolicy = Policy{Free: 3, Base: time.Second, Max: 10 * time.Second, Window: time.Minute}
+
+func TestPolicyDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 0},
+		{3, 0},
+		{4, time.Second},
+		{5, 2 * time.Second},
+		{6, 4 * time.Second},
+		{7, 8 * time.Second},
+		{8, 10 * time.Second},
+		{1000, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := testPolicy.Delay(tt.attempts); got != tt.want {
+			t.Errorf("Delay(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+// fakeClock подменяет время MemoryLimiter.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestLimiter() (*MemoryLimiter, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l := NewMemoryLimiter(testPolicy)
+	l.now = clock.now
+	return l, clock
+}
+
+func TestMemoryLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	type step struct {
+		op      string // reserve, refund, reset или wait
+		wait    time.Duration
+		blocked time.Duration // ожидаемый ответ Reserve
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"free attempts are not delayed", []step{
+			{op: "reserve"}, {op: "reserve"}, {op: "reserve"},
+			{op: "reserve"},
+			{op: "reserve", blocked: time.Second},
+		}},
+		{"blocked attempt is not counted", []step{
+			{op: "reserve"}, {op: "reserve"}, {op: "reserve"}, {op: "reserve"},
+			{op: "reserve", blocked: time.Second},
+			{op: "wait", wait: time.Second},
+			{op: "reserve"},
+			{op: "reserve", blocked: 2 * time.Second},
+		}},
+		{"delay grows to max", []step{
+			{op: "reserve"}, {op: "reserve"}, {op: "reserve"}, {op: "reserve"},
+			{op: "wait", wait: time.Second}, {op: "reserve"},
+			{op: "wait", wait: 2 * time.Second}, {op: "reserve"},
+			{op: "wait", wait: 4 * time.Second}, {op: "reserve"},
+			{op: "wait", wait: 8 * time.Second}, {op: "reserve"},
+			{op: "reserve", blocked: 10 * time.Second},
+		}},
+		{"refund lifts the delay of the last attempt", []step{
+			{op: "reserve"}, {op: "reserve"}, {op: "reserve"}, {op: "reserve"},
+			{op: "refund"},
+			{op: "reserve"},
+			{op: "reserve", blocked: time.Second},
+		}},
+		{"reset clears the counter", []step{
+			{op: "reserve"}, {op: "reserve"}, {op: "reserve"}, {op: "reserve"},
+			{op: "reset"},
+			{op: "reserve"}, {op: "reserve"}, {op: "reserve"},
+			{op: "reserve"},
+		}},
+		{"counter expires after window", []step{
+			{op: "reserve"}, {op: "reserve"}, {op: "reserve"}, {op: "reserve"},
+			{op: "wait", wait: time.Minute + time.Second},
+			{op: "reserve"}, {op: "reserve"}, {op: "reserve"},
+			{op: "reserve"},
+		}},
+		{"refund of unknown key is a no-op", []step{
+			{op: "refund"},
+			{op: "reserve"}, {op: "reserve"}, {op: "reserve"}, {op: "reserve"},
+			{op: "reserve", blocked: time.Second},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, clock := newTestLimiter()
+			for i, s := range tt.steps {
+				var err error
+				switch s.op {
+				case "reserve":
+					var wait time.Duration
+					wait, err = l.Reserve(ctx, "key")
+					if wait != s.blocked {
+						t.Fatalf("step %d: Reserve = %s, want %s", i, wait, s.blocked)
+					}
+				case "refund":
+					err = l.Refund(ctx, "key")
+				case "reset":
+					err = l.Reset(ctx, "key")
+				case "wait":
+					clock.advance(s.wait)
+				}
+				if err != nil {
+					t.Fatalf("step %d: %s: %v", i, s.op, err)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryLimiterKeysAreIndependent(t *testing.T) {
+	ctx := context.Background()
+	l, _ := newTestLimiter()
+	for range testPolicy.Free + 1 {
+		if _, err := l.Reserve(ctx, "a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if wait, _ := l.Reserve(ctx, "a"); wait == 0 {
+		t.Errorf("key a is not blocked")
+	}
+	if wait, _ := l.Reserve(ctx, "b"); wait != 0 {
+		t.Errorf("key b is blocked for %s", wait)
+	}
+}
+
+func TestDelayTable(t *testing.T) {
+	got := delayTable(testPolicy)
+	want := []int64{1000, 2000, 4000, 8000, 10000}
+	if len(got) != len(want) {
+		t.Fatalf("delayTable = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("delayTable[%d] = %v, want %d", i, got[i], want[i])
+		}
+	}
+}
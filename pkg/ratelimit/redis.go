@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisLimiter хранит счётчики в Redis, чтобы их видели все экземпляры сервиса.
+// Для ключа заводятся счётчик попыток с TTL Window и отметка блокировки с TTL задержки.
+type RedisLimiter struct {
+	client redis.UniversalClient
+	prefix string
+	policy Policy
+	// delays — задержки в миллисекундах после Free+1, Free+2, ... попыток;
+	// последняя действует и дальше. Передаются скриптам, чтобы не повторять Delay на Lua.
+	delays []any
+}
+
+func NewRedisLimiter(client redis.UniversalClient, prefix string, policy Policy) *RedisLimiter {
+	return &RedisLimiter{client: client, prefix: prefix, policy: policy, delays: delayTable(policy)}
+}
+
+// maxDelaySteps ограничивает таблицу задержек, если Base нулевая и Max не достигается.
+const maxDelaySteps = 64
+
+func delayTable(p Policy) []any {
+	var delays []any
+	for over := 1; over <= maxDelaySteps; over++ {
+		d := p.Delay(p.Free + over)
+		delays = append(delays, d.Milliseconds())
+		if d >= p.Max {
+			break
+		}
+	}
+	return delays
+}
+
+// reserveScript: KEYS — счётчик и блокировка; ARGV — Window в мс, Free, таблица задержек.
+// Возвращает оставшуюся блокировку в мс или 0, если попытка учтена.
+var reserveScript = redis.NewScript(`
+local wait = redis.call('PTTL', KEYS[2])
+if wait > 0 then
+	return wait
+end
+local n = redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[1])
+local over = n - tonumber(ARGV[2])
+if over > 0 then
+	local delay = tonumber(ARGV[math.min(over, #ARGV - 2) + 2])
+	if delay > 0 then
+		redis.call('SET', KEYS[2], 1, 'PX', delay)
+	end
+end
+return 0
+`)
+
+// refundScript уменьшает счётчик и снимает блокировку, если без этой попытки её бы не было.
+var refundScript = redis.NewScript(`
+local n = tonumber(redis.call('GET', KEYS[1]) or '0')
+if n <= 0 then
+	return 0
+end
+n = redis.call('DECR', KEYS[1])
+if n <= tonumber(ARGV[1]) then
+	redis.call('DEL', KEYS[2])
+end
+return n
+`)
+
+func (l *RedisLimiter) Reserve(ctx context.Context, key string) (time.Duration, error) {
+	args := append([]any{l.policy.Window.Milliseconds(), l.policy.Free}, l.delays...)
+	wait, err := reserveScript.Run(ctx, l.client, []string{l.countKey(key), l.lockKey(key)}, args...).Int64()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(wait) * time.Millisecond, nil
+}
+
+func (l *RedisLimiter) Refund(ctx context.Context, key string) error {
+	return refundScript.Run(ctx, l.client, []string{l.countKey(key), l.lockKey(key)}, l.policy.Free).Err()
+}
+
+func (l *RedisLimiter) Reset(ctx context.Context, key string) error {
+	return l.client.Del(ctx, l.countKey(key), l.lockKey(key)).Err()
+}
+
+func (l *RedisLimiter) countKey(key string) string { return l.prefix + "n:" + key }
+func (l *RedisLimiter) lockKey(key string) string  { return l.prefix + "l:" + key }
@@ -0,0 +1,41 @@
+// Package rbac — роли и права, общие для сервисов и gateway.
+// Какие права у какой роли, хранится в БД user-service (таблица role_permissions)
+// и попадает в токен при входе.
+package rbac
+
+type Role string
+
+const (
+	RoleAdmin   Role = "admin"
+	RoleManager Role = "manager"
+	RoleMember  Role = "member"
+	RoleViewer  Role = "viewer"
+)
+
+func (r Role) Valid() bool {
+	switch r {
+	case RoleAdmin, RoleManager, RoleMember, RoleViewer:
+		return true
+	}
+	return false
+}
+
+const (
+	TasksRead   = "tasks:read"
+	TasksCreate = "tasks:create"
+	TasksUpdate = "tasks:update"
+	TasksDelete = "tasks:delete"
+	TasksAssign = "tasks:assign"
+	UsersRead   = "users:read"
+	UsersManage = "users:manage"
+)
+
+// Has — есть ли permission среди permissions.
+func Has(permissions []string, permission string) bool {
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
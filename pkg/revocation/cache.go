@@ -0,0 +1,101 @@
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CachedStore держит в памяти ответы общего хранилища, чтобы не ходить
+// в него на каждый запрос. Отозванные токены кешируются до истечения,
+// неотозванные — на negativeTTL: столько отзыв может идти до этого экземпляра.
+type CachedStore struct {
+	backend     Store
+	local       *MemoryStore
+	negativeTTL time.Duration
+
+	mu      sync.Mutex
+	checked map[string]time.Time
+	now     func() time.Time
+}
+
+func NewCachedStore(backend Store, negativeTTL time.Duration) *CachedStore {
+	return &CachedStore{
+		backend:     backend,
+		local:       NewMemoryStore(),
+		negativeTTL: negativeTTL,
+		checked:     map[string]time.Time{},
+		now:         time.Now,
+	}
+}
+
+func (s *CachedStore) Revoke(ctx context.Context, id string, expiresAt time.Time) error {
+	if err := s.backend.Revoke(ctx, id, expiresAt); err != nil {
+		return err
+	}
+	s.forget(id)
+	return s.local.Revoke(ctx, id, expiresAt)
+}
+
+func (s *CachedStore) IsRevoked(ctx context.Context, id string) (bool, error) {
+	if revoked, _ := s.local.IsRevoked(ctx, id); revoked {
+		return true, nil
+	}
+	if s.recentlyChecked(id) {
+		return false, nil
+	}
+
+	revoked, err := s.backend.IsRevoked(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if revoked {
+		// отзыв не отменяется; срок токена здесь неизвестен, поэтому
+		// держим запись с запасом — истёкший токен всё равно не пройдёт проверку
+		return true, s.local.Revoke(ctx, id, s.now().Add(time.Hour))
+	}
+
+	s.mu.Lock()
+	if len(s.checked) >= maxChecked {
+		s.sweep()
+	}
+	s.checked[id] = s.now().Add(s.negativeTTL)
+	s.mu.Unlock()
+	return false, nil
+}
+
+// maxChecked — после скольких записей чистить устаревшие отрицательные ответы.
+const maxChecked = 10000
+
+// sweep удаляет устаревшие отрицательные ответы. Вызывается под s.mu.
+func (s *CachedStore) sweep() {
+	now := s.now()
+	for id, until := range s.checked {
+		if now.After(until) {
+			delete(s.checked, id)
+		}
+	}
+}
+
+func (s *CachedStore) recentlyChecked(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until, ok := s.checked[id]
+	if ok && s.now().After(until) {
+		delete(s.checked, id)
+		return false
+	}
+	return ok
+}
+
+func (s *CachedStore) forget(id string) {
+	s.mu.Lock()
+	delete(s.checked, id)
+	s.mu.Unlock()
+}
+
+// Ping проверяет общее хранилище, локальный кеш его не заменяет.
+func (s *CachedStore) Ping(ctx context.Context) error {
+	return Ping(ctx, s.backend)
+}
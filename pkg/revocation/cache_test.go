@@ -0,0 +1,147 @@
+package revocation
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// countingStore — общее хранилище, которое считает обращения IsRevoked.
+type countingStore struct {
+	*MemoryStore
+	calls int
+	err   error
+}
+
+func (s *countingStore) IsRevoked(ctx context.Context, id string) (bool, error) {
+	s.calls++
+	if s.err != nil {
+		return false, s.err
+	}
+	return s.MemoryStore.IsRevoked(ctx, id)
+}
+
+func newTestCache(ttl time.Duration) (*CachedStore, *countingStore, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	backend := &countingStore{MemoryStore: NewMemoryStore()}
+	backend.now = clock
+	c := NewCachedStore(backend, ttl)
+	c.now = clock
+	c.local.now = clock
+	return c, backend, &now
+}
+
+func TestCachedStore(t *testing.T) {
+	ctx := context.Background()
+	const ttl = 5 * time.Second
+
+	tests := []struct {
+		name string
+		// run выполняет сценарий и возвращает ответ последнего IsRevoked
+		run       func(c *CachedStore, backend *countingStore, now *time.Time) (bool, error)
+		want      bool
+		wantCalls int
+	}{
+		{
+			name: "negative answer is cached",
+			run: func(c *CachedStore, _ *countingStore, _ *time.Time) (bool, error) {
+				c.IsRevoked(ctx, "jti")
+				return c.IsRevoked(ctx, "jti")
+			},
+			want: false, wantCalls: 1,
+		},
+		{
+			name: "negative answer expires after ttl",
+			run: func(c *CachedStore, _ *countingStore, now *time.Time) (bool, error) {
+				c.IsRevoked(ctx, "jti")
+				*now = now.Add(ttl + time.Millisecond)
+				return c.IsRevoked(ctx, "jti")
+			},
+			want: false, wantCalls: 2,
+		},
+		{
+			name: "revocation by another instance is seen after ttl",
+			run: func(c *CachedStore, backend *countingStore, now *time.Time) (bool, error) {
+				c.IsRevoked(ctx, "jti")
+				backend.Revoke(ctx, "jti", now.Add(time.Hour))
+				if revoked, _ := c.IsRevoked(ctx, "jti"); revoked {
+					t.Errorf("revocation seen before ttl, negative answer was not cached")
+				}
+				*now = now.Add(ttl + time.Millisecond)
+				return c.IsRevoked(ctx, "jti")
+			},
+			want: true, wantCalls: 2,
+		},
+		{
+			name: "own revocation drops negative answer",
+			run: func(c *CachedStore, _ *countingStore, now *time.Time) (bool, error) {
+				c.IsRevoked(ctx, "jti")
+				c.Revoke(ctx, "jti", now.Add(time.Hour))
+				return c.IsRevoked(ctx, "jti")
+			},
+			want: true, wantCalls: 1,
+		},
+		{
+			name: "positive answer is cached",
+			run: func(c *CachedStore, backend *countingStore, now *time.Time) (bool, error) {
+				backend.Revoke(ctx, "jti", now.Add(time.Hour))
+				c.IsRevoked(ctx, "jti")
+				*now = now.Add(time.Minute)
+				return c.IsRevoked(ctx, "jti")
+			},
+			want: true, wantCalls: 1,
+		},
+		{
+			name: "other ids are not cached",
+			run: func(c *CachedStore, _ *countingStore, _ *time.Time) (bool, error) {
+				c.IsRevoked(ctx, "a")
+				return c.IsRevoked(ctx, "b")
+			},
+			want: false, wantCalls: 2,
+		},
+		{
+			name: "errors are not cached",
+			run: func(c *CachedStore, backend *countingStore, _ *time.Time) (bool, error) {
+				backend.err = errors.New("redis down")
+				if _, err := c.IsRevoked(ctx, "jti"); err == nil {
+					t.Errorf("backend error was swallowed")
+				}
+				backend.err = nil
+				return c.IsRevoked(ctx, "jti")
+			},
+			want: false, wantCalls: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, backend, now := newTestCache(ttl)
+			got, err := tt.run(c, backend, now)
+			if err != nil {
+				t.Fatalf("IsRevoked: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsRevoked = %v, want %v", got, tt.want)
+			}
+			if backend.calls != tt.wantCalls {
+				t.Errorf("backend called %d times, want %d", backend.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCachedStoreSweep(t *testing.T) {
+	ctx := context.Background()
+	c, _, now := newTestCache(time.Second)
+	for i := range maxChecked {
+		c.checked[strconv.Itoa(i)] = now.Add(time.Second)
+	}
+	*now = now.Add(2 * time.Second)
+	c.IsRevoked(ctx, "fresh")
+	if len(c.checked) != 1 {
+		t.Errorf("%d negative answers left after sweep, want 1", len(c.checked))
+	}
+}
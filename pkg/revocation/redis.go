@@ -0,0 +1,37 @@
+package revocation
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore хранит отозванные токены в Redis (или совместимом сервере),
+// чтобы отзыв был виден всем экземплярам сервисов.
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+func NewRedisStore(client redis.UniversalClient) *RedisStore {
+	return &RedisStore{client: client, prefix: "revoked:"}
+}
+
+func (s *RedisStore) Revoke(ctx context.Context, id string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return s.client.Set(ctx, s.prefix+id, 1, ttl).Err()
+}
+
+func (s *RedisStore) IsRevoked(ctx context.Context, id string) (bool, error) {
+	n, err := s.client.Exists(ctx, s.prefix+id).Result()
+	return n > 0, err
+}
+
+// Ping проверяет, что Redis отвечает.
+func (s *RedisStore) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
@@ -0,0 +1,78 @@
+// Package revocation — список отозванных токенов (по jti) и сессий
+// до истечения их срока.
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Store хранит ID отозванных токенов. Запись нужна только до expiresAt:
+// после этого токен и так не пройдёт проверку exp.
+type Store interface {
+	Revoke(ctx context.Context, id string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, id string) (bool, error)
+}
+
+// SessionKey — ID, под которым в Store отзывается сессия целиком:
+// все access token с этим sid. Не пересекается с jti токенов.
+func SessionKey(sessionID string) string {
+	return "sid:" + sessionID
+}
+
+// MemoryStore — хранилище в памяти процесса. Отзывы из других процессов
+// в нём не видны, поэтому между gateway и user-service нужен Redis (New).
+type MemoryStore struct {
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+	now     func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{revoked: map[string]time.Time{}, now: time.Now}
+}
+
+func (s *MemoryStore) Revoke(_ context.Context, id string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.revoked[id] = expiresAt
+	s.cleanup()
+	return nil
+}
+
+func (s *MemoryStore) IsRevoked(_ context.Context, id string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	exp, ok := s.revoked[id]
+	return ok && s.now().Before(exp), nil
+}
+
+// cleanup удаляет записи об истёкших токенах. Вызывается под s.mu.
+func (s *MemoryStore) cleanup() {
+	now := s.now()
+	for id, exp := range s.revoked {
+		if !now.Before(exp) {
+			delete(s.revoked, id)
+		}
+	}
+}
+
+// New возвращает хранилище в Redis с локальным кешем отрицательных ответов
+// на несколько секунд.
+func New(redisAddr string) Store {
+	return NewCachedStore(NewRedisStore(redis.NewClient(&redis.Options{Addr: redisAddr})), 5*time.Second)
+}
+
+// Ping проверяет доступность хранилища, если оно это умеет.
+// Хранилище в памяти процесса доступно всегда.
+func Ping(ctx context.Context, s Store) error {
+	if p, ok := s.(interface{ Ping(context.Context) error }); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Package shutdown запускает серверы до сигнала остановки и корректно
+// их гасит: новые соединения не принимаются, начатые запросы дорабатывают.
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// Context отменяется при SIGINT или SIGTERM. Повторный сигнал после
+// отмены завершает процесс без ожидания, как обычно.
+func Context() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// ServeGRPC обслуживает lis до отмены ctx, затем вызывает GracefulStop.
+// Если запросы не завершились за timeout, оставшиеся соединения рвутся.
+func ServeGRPC(ctx context.Context, srv *grpc.Server, lis net.Listener, timeout time.Duration) error {
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(lis) }()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("остановка gRPC сервера, ожидание запросов до %s", timeout)
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		log.Println("запросы не завершились вовремя, соединения закрываются принудительно")
+		srv.Stop()
+		<-stopped
+	}
+	return <-errc
+}
+
+// ServeHTTP обслуживает srv до отмены ctx, затем вызывает Shutdown с
+// ограничением timeout.
+func ServeHTTP(ctx context.Context, srv *http.Server, timeout time.Duration) error {
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("остановка HTTP сервера, ожидание запросов до %s", timeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Println("запросы не завершились вовремя, соединения закрываются принудительно")
+		srv.Close()
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
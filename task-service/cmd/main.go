@@ -3,27 +3,95 @@ package main
 import (
 	"log"
 	"net"
+	"os"
 
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/grpcauth"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/healthcheck"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/migrate"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/shutdown"
+	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
 	taskpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/task"
+	"github.com/Murodkadirkhanoff/taqsym.uz/task-service/config"
+	"github.com/Murodkadirkhanoff/taqsym.uz/task-service/internal/domain"
 	"github.com/Murodkadirkhanoff/taqsym.uz/task-service/internal/handler"
 	"github.com/Murodkadirkhanoff/taqsym.uz/task-service/internal/repository"
 	"github.com/Murodkadirkhanoff/taqsym.uz/task-service/internal/usecase"
+	"github.com/Murodkadirkhanoff/taqsym.uz/task-service/migrations"
 	"github.com/Murodkadirkhanoff/taqsym.uz/task-service/pkg/db"
 	_ "github.com/lib/pq"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
 )
 
 func main() {
-	database, err := db.NewPostgres()
+	// `task-service migrate up|down [N]|status|version` управляет схемой и завершается
+	args := os.Args[1:]
+	var migrateCmd *migrate.Command
+	if len(args) > 0 && args[0] == "migrate" {
+		cmd, rest, err := migrate.ParseCommand(args[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		migrateCmd, args = &cmd, rest
+	}
+
+	cfg, err := config.Load(args)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := shutdown.Context()
+	defer stop()
+
+	database, err := db.NewPostgres(cfg.Database.DSN())
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer database.Close()
 	log.Println("Успешное подключение к базе данных!")
 
+	migrator, err := migrate.New(database, migrations.FS, "task-service")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if migrateCmd != nil {
+		if err := migrateCmd.Run(ctx, migrator, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if cfg.AutoMigrate {
+		n, err := migrator.Up(ctx)
+		if err != nil {
+			log.Fatalf("не удалось применить миграции: %v", err)
+		}
+		log.Printf("применено миграций: %d", n)
+	}
+
+	transitions := domain.DefaultTransitions
+	if len(cfg.Transitions) > 0 {
+		if transitions, err = domain.ParseTransitions(cfg.Transitions); err != nil {
+			log.Fatalf("неверная таблица переходов статусов: %v", err)
+		}
+	}
+
+	identityKey := []byte(cfg.Gateway.IdentityKey)
+
+	// участников пространства знает только user-service; вызовы идут
+	// от имени того же пользователя, личность подписывается тем же ключом
+	userConn, err := grpc.NewClient(cfg.UserService.Addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(grpcauth.UnaryClientInterceptor(identityKey)),
+	)
+	if err != nil {
+		log.Fatalf("не удалось подключиться к user-service: %v", err)
+	}
+	defer userConn.Close()
+
 	repo := repository.NewTaskRepository(database)
-	uc := usecase.NewTaskUsecase(repo)
+	members := repository.NewMembership(authpb.NewAuthServiceClient(userConn))
+	uc := usecase.NewTaskUsecase(repo, members, transitions)
 	h := handler.NewTaskHandler(uc)
 
 	// r := router.SetupRouter(h)
@@ -33,17 +101,27 @@ func main() {
 	// fmt.Println(port)
 	// r.Run(":" + port)
 
-	lis, err := net.Listen("tcp", ":50051")
+	lis, err := net.Listen("tcp", cfg.GRPCAddr)
 	if err != nil {
 		log.Fatalf("не удалось слушать: %v", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	auth := grpcauth.GatewayIdentity(identityKey)
+
+	healthPublic := grpcauth.WithPublicMethods(healthcheck.Methods...)
+
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(grpcauth.UnaryServerInterceptor(auth, healthPublic)),
+		grpc.ChainStreamInterceptor(grpcauth.StreamServerInterceptor(auth, healthPublic)),
+	)
 	taskpb.RegisterTaskServiceServer(grpcServer, h)
+	health := healthcheck.Register(grpcServer, database.PingContext, taskpb.TaskService_ServiceDesc.ServiceName)
+	go health.Run(ctx, cfg.HealthInterval)
 	reflection.Register(grpcServer)
 
-	log.Println("AuthService запущен на порту :50051")
-	if err := grpcServer.Serve(lis); err != nil {
+	log.Printf("TaskService запущен на %s", cfg.GRPCAddr)
+	if err := shutdown.ServeGRPC(ctx, grpcServer, lis, cfg.ShutdownTimeout); err != nil {
 		log.Fatalf("ошибка запуска gRPC сервера: %v", err)
 	}
+	log.Println("TaskService остановлен")
 }
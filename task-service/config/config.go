@@ -0,0 +1,39 @@
+// Package config — настройки task-service.
+package config
+
+import (
+	"time"
+
+	pkgconfig "github.com/Murodkadirkhanoff/taqsym.uz/pkg/config"
+)
+
+type Config struct {
+	GRPCAddr string `mapstructure:"grpc_addr" default:":50051" usage:"адрес gRPC сервера"`
+	// ShutdownTimeout — сколько ждать завершения начатых запросов при остановке.
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout" default:"15s" usage:"ожидание запросов при остановке"`
+	// HealthInterval — как часто проверять базу для gRPC health сервиса.
+	HealthInterval time.Duration      `mapstructure:"health_interval" default:"5s" usage:"период проверки зависимостей"`
+	Database       pkgconfig.Postgres `mapstructure:"database"`
+	// AutoMigrate применяет встроенные миграции при старте сервиса.
+	AutoMigrate bool `mapstructure:"auto_migrate" usage:"применить миграции при старте"`
+	// Transitions — разрешённые переходы статусов задач вида "todo -> in_progress".
+	// Пусто — стандартный процесс domain.DefaultTransitions.
+	Transitions []string `mapstructure:"transitions" usage:"переходы статусов задач, from -> to"`
+	Gateway     struct {
+		// IdentityKey — общий с gateway ключ подписи личности пользователя.
+		IdentityKey string `mapstructure:"identity_key" validate:"required"`
+	} `mapstructure:"gateway"`
+	// UserService отвечает на вопрос, кто состоит в пространстве.
+	UserService struct {
+		Addr string `mapstructure:"addr" default:"localhost:50051" usage:"адрес user-service"`
+	} `mapstructure:"user_service"`
+}
+
+// Load читает настройки из файла, окружения и флагов args.
+func Load(args []string) (*Config, error) {
+	var cfg Config
+	if err := pkgconfig.Load("task-service", &cfg, args); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
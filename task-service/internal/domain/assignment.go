@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+)
+
+var (
+	ErrInvalidAssignment  = apperr.InvalidArgument("INVALID_ASSIGNMENT", "invalid assignment request")
+	ErrNotWorkspaceMember = apperr.InvalidArgument("NOT_WORKSPACE_MEMBER", "user is not a workspace member")
+)
+
+type Strategy string
+
+const (
+	StrategyManual      Strategy = "manual"
+	StrategyRoundRobin  Strategy = "round_robin"
+	StrategyLeastLoaded Strategy = "least_loaded"
+	StrategyWeighted    Strategy = "weighted"
+)
+
+// Member — участник команды, среди которых распределяются задачи.
+type Member struct {
+	UserID   int64
+	Capacity int
+}
+
+// Membership отвечает, кто состоит в пространстве. Членство хранит
+// user-service, task-service его только спрашивает.
+type Membership interface {
+	// Members возвращает ID участников пространства workspaceID.
+	// Вызывающий из ctx сам должен в нём состоять.
+	Members(ctx context.Context, workspaceID int64) (map[int64]bool, error)
+}
+
+// Assignment — запись о назначении задачи и причине выбора исполнителя.
+type Assignment struct {
+	WorkspaceID int64
+	TaskID      int64
+	AssigneeID  int64
+	AssignedBy  int64
+	Strategy    Strategy
+	Reason      string
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+)
+
+var ErrInvalidFilter = apperr.InvalidArgument("INVALID_FILTER", "invalid list filter")
+
+type SortField string
+
+const (
+	SortByCreatedAt SortField = "created_at"
+	SortByUpdatedAt SortField = "updated_at"
+	SortByTitle     SortField = "title"
+	SortByID        SortField = "id"
+)
+
+// Cursor — позиция последней выданной задачи для keyset пагинации.
+// Value — значение поля сортировки в текстовом виде.
+type Cursor struct {
+	Value string
+	ID    int64
+}
+
+// TaskFilter описывает выборку задач. Нулевые значения фильтров не применяются.
+type TaskFilter struct {
+	// WorkspaceID обязателен: выборка никогда не выходит за пределы пространства.
+	WorkspaceID int64
+	// VisibleTo ограничивает выборку задачами, где пользователь автор или исполнитель.
+	VisibleTo     int64
+	AuthorID      int64
+	AssigneeID    int64
+	Statuses      []Status
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Query         string
+
+	SortBy SortField
+	Desc   bool
+
+	Limit int
+	After *Cursor
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+)
+
+var (
+	ErrInvalidStatus     = apperr.InvalidField("status", "INVALID_STATUS", "unknown task status")
+	ErrInvalidTransition = apperr.Conflict("TRANSITION_NOT_ALLOWED", "task status transition is not allowed")
+)
+
+type Status string
+
+const (
+	StatusTodo       Status = "todo"
+	StatusInProgress Status = "in_progress"
+	StatusReview     Status = "review"
+	StatusDone       Status = "done"
+	StatusCancelled  Status = "cancelled"
+)
+
+func (s Status) Valid() bool {
+	switch s {
+	case StatusTodo, StatusInProgress, StatusReview, StatusDone, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// Transitions — таблица переходов: из какого статуса в какие можно перейти.
+type Transitions map[Status][]Status
+
+func (t Transitions) Allowed(from, to Status) bool {
+	for _, s := range t[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTransitions собирает таблицу из переходов вида "todo -> in_progress",
+// например из настроек сервиса. Все упомянутые статусы должны существовать.
+func ParseTransitions(edges []string) (Transitions, error) {
+	t := make(Transitions)
+	for _, edge := range edges {
+		from, to, ok := strings.Cut(edge, "->")
+		if !ok {
+			return nil, fmt.Errorf("transition %q: expected \"from -> to\"", edge)
+		}
+		f, s := Status(strings.TrimSpace(from)), Status(strings.TrimSpace(to))
+		for _, st := range []Status{f, s} {
+			if !st.Valid() {
+				return nil, fmt.Errorf("transition %q: unknown status %q", edge, st)
+			}
+		}
+		if f == s {
+			return nil, fmt.Errorf("transition %q: status can not transition to itself", edge)
+		}
+		if !t.Allowed(f, s) {
+			t[f] = append(t[f], s)
+		}
+	}
+	return t, nil
+}
+
+// DefaultTransitions — стандартный рабочий процесс доски.
+// done и cancelled — конечные статусы.
+var DefaultTransitions = Transitions{
+	StatusTodo:       {StatusInProgress, StatusCancelled},
+	StatusInProgress: {StatusTodo, StatusReview, StatusCancelled},
+	StatusReview:     {StatusInProgress, StatusDone, StatusCancelled},
+}
@@ -0,0 +1,55 @@
+package domain
+
+import "testing"
+
+func TestTransitionsAllowed(t *testing.T) {
+	tests := []struct {
+		from, to Status
+		want     bool
+	}{
+		{StatusTodo, StatusInProgress, true},
+		{StatusTodo, StatusCancelled, true},
+		{StatusTodo, StatusDone, false},
+		{StatusInProgress, StatusTodo, true},
+		{StatusInProgress, StatusReview, true},
+		{StatusInProgress, StatusDone, false},
+		{StatusReview, StatusDone, true},
+		{StatusReview, StatusTodo, false},
+		{StatusDone, StatusTodo, false},
+		{StatusCancelled, StatusTodo, false},
+		{StatusTodo, StatusTodo, false},
+		{"unknown", StatusTodo, false},
+	}
+	for _, tt := range tests {
+		if got := DefaultTransitions.Allowed(tt.from, tt.to); got != tt.want {
+			t.Errorf("Allowed(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestParseTransitions(t *testing.T) {
+	tr, err := ParseTransitions([]string{"todo -> done", " done->todo ", "todo -> done"})
+	if err != nil {
+		t.Fatalf("ParseTransitions: %v", err)
+	}
+	if !tr.Allowed(StatusTodo, StatusDone) || !tr.Allowed(StatusDone, StatusTodo) {
+		t.Errorf("table %v misses configured transitions", tr)
+	}
+	if tr.Allowed(StatusTodo, StatusInProgress) {
+		t.Errorf("table %v allows a transition that was not configured", tr)
+	}
+	if n := len(tr[StatusTodo]); n != 1 {
+		t.Errorf("duplicate edge stored %d times", n)
+	}
+
+	for _, edges := range [][]string{
+		{"todo"},
+		{"todo -> archived"},
+		{"-> done"},
+		{"review -> review"},
+	} {
+		if _, err := ParseTransitions(edges); err == nil {
+			t.Errorf("ParseTransitions(%q) accepted an invalid table", edges)
+		}
+	}
+}
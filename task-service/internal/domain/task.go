@@ -1,20 +1,81 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+)
+
+var (
+	ErrTaskNotFound = apperr.NotFound("TASK_NOT_FOUND", "task not found")
+	ErrForbidden    = apperr.PermissionDenied("TASK_FORBIDDEN", "access to task denied")
+	ErrNoWorkspace  = apperr.InvalidArgument("NO_WORKSPACE", "workspace is not selected")
+)
 
 type Task struct {
 	ID          int64  `json:"id"`
+	WorkspaceID int64  `json:"workspace_id"`
 	Title       string `json:"title" binding:"required"`
 	Description string `json:"description" binding:"required"`
 	UserID      int64  `json:"user_id" binding:"required"`
+	Status      Status `json:"status"`
+	// AssigneeID — исполнитель задачи, 0 если не назначен.
+	AssigneeID       int64     `json:"assignee_id"`
+	AssignmentReason string    `json:"assignment_reason"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// TaskUpdate — частичное обновление задачи, nil означает "не менять".
+type TaskUpdate struct {
+	Title       *string
+	Description *string
+}
+
+// Actor — пользователь, от имени которого идёт вызов, и его активное пространство.
+type Actor struct {
+	UserID      int64
+	WorkspaceID int64
+}
+
+// CanView — пользователь автор задачи или её исполнитель.
+func (t *Task) CanView(userID int64) bool {
+	return t.UserID == userID || (t.AssigneeID != 0 && t.AssigneeID == userID)
+}
+
+// IsOwner — пользователь автор задачи.
+func (t *Task) IsOwner(userID int64) bool {
+	return t.UserID == userID
 }
 
 type Repository interface {
 	Create(ctx context.Context, task *Task) error
-	List(ctx context.Context) ([]*Task, error)
+	// List возвращает не более filter.Limit задач, начиная после filter.After.
+	List(ctx context.Context, filter TaskFilter) ([]*Task, error)
+	// Все методы, кроме Create, ограничены пространством workspaceID:
+	// задачи чужого пространства для них не существуют.
+	GetByID(ctx context.Context, workspaceID, id int64) (*Task, error)
+	Update(ctx context.Context, task *Task) error
+	Delete(ctx context.Context, workspaceID, id int64) error
+	// UpdateStatus меняет статус, только если текущий статус всё ещё from.
+	UpdateStatus(ctx context.Context, workspaceID, id int64, from, to Status) error
+	// Assign назначает исполнителя и сохраняет запись в истории назначений.
+	Assign(ctx context.Context, a *Assignment) error
+	// OpenTaskCounts возвращает число незавершённых задач у каждого пользователя.
+	OpenTaskCounts(ctx context.Context, workspaceID int64, userIDs []int64) (map[int64]int, error)
+	// LastAssignee возвращает последнего из userIDs, получившего задачу по стратегии.
+	LastAssignee(ctx context.Context, workspaceID int64, strategy Strategy, userIDs []int64) (int64, error)
 }
 
 type Usecase interface {
 	Create(ctx context.Context, task *Task) error
-	List(ctx context.Context) ([]*Task, error)
+	// List возвращает страницу задач и токен следующей страницы.
+	List(ctx context.Context, filter TaskFilter, pageToken string) ([]*Task, string, error)
+	Get(ctx context.Context, actor Actor, id int64) (*Task, error)
+	Update(ctx context.Context, actor Actor, id int64, upd TaskUpdate) (*Task, error)
+	Delete(ctx context.Context, actor Actor, id int64) error
+	Transition(ctx context.Context, actor Actor, id int64, to Status) (*Task, error)
+	Assign(ctx context.Context, actor Actor, id, assigneeID int64) (*Task, error)
+	AutoAssign(ctx context.Context, actor Actor, id int64, strategy Strategy, team []Member) (*Task, error)
 }
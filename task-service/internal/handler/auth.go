@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/grpcauth"
+	"github.com/Murodkadirkhanoff/taqsym.uz/task-service/internal/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// currentUser возвращает пользователя, которого аутентифицировал interceptor,
+// и его активное пространство. Без пространства с задачами работать нельзя.
+func currentUser(ctx context.Context) (domain.Actor, error) {
+	p, ok := grpcauth.FromContext(ctx)
+	if !ok {
+		return domain.Actor{}, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+	if p.WorkspaceID == 0 {
+		return domain.Actor{}, apperr.ToStatus(domain.ErrNoWorkspace)
+	}
+	return domain.Actor{UserID: p.UserID, WorkspaceID: p.WorkspaceID}, nil
+}
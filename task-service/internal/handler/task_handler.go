@@ -2,10 +2,13 @@ package handler
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"strings"
 
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
 	taskpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/task"
 	"github.com/Murodkadirkhanoff/taqsym.uz/task-service/internal/domain"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type TaskHandler struct {
@@ -18,46 +21,189 @@ func NewTaskHandler(uc domain.Usecase) *TaskHandler {
 }
 
 func (h *TaskHandler) Create(ctx context.Context, request *taskpb.CreateTaskRequest) (*taskpb.CreateTaskResponse, error) {
+	actor, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	task := domain.Task{
 		Title:       request.GetTitle(),
 		Description: request.GetDescription(),
-		UserID:      request.GetUserId(),
+		UserID:      actor.UserID,
+		WorkspaceID: actor.WorkspaceID,
 	}
-	h.uc.Create(ctx, &task)
-	protoTask := &taskpb.Task{
-		Id:          task.ID,
-		Title:       task.Title,
-		Description: task.Description,
-		UserId:      task.UserID,
+	if err := h.uc.Create(ctx, &task); err != nil {
+		return nil, apperr.ToStatus(err)
 	}
 
 	return &taskpb.CreateTaskResponse{
-		Task: protoTask,
+		Task: toProtoTask(&task),
 	}, nil
 }
 
 func (h *TaskHandler) ListTasks(ctx context.Context, request *taskpb.ListTasksRequest) (*taskpb.ListTasksResponse, error) {
-	tasks, err := h.uc.List(ctx)
+	actor, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := domain.TaskFilter{
+		WorkspaceID: actor.WorkspaceID,
+		VisibleTo:   actor.UserID,
+		AuthorID:    request.GetAuthorId(),
+		AssigneeID:  request.GetAssigneeId(),
+		Query:       request.GetQuery(),
+		Limit:       int(request.GetPageSize()),
+	}
+	for _, st := range request.GetStatuses() {
+		filter.Statuses = append(filter.Statuses, domain.Status(st))
+	}
+	if request.GetCreatedAfter() != nil {
+		filter.CreatedAfter = request.GetCreatedAfter().AsTime()
+	}
+	if request.GetCreatedBefore() != nil {
+		filter.CreatedBefore = request.GetCreatedBefore().AsTime()
+	}
+	if orderBy := request.GetOrderBy(); orderBy != "" {
+		filter.SortBy = domain.SortField(strings.TrimPrefix(orderBy, "-"))
+		filter.Desc = strings.HasPrefix(orderBy, "-")
+	}
+
+	tasks, next, err := h.uc.List(ctx, filter, request.GetPageToken())
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
 
 	protoTasks := []*taskpb.Task{}
 
 	for _, task := range tasks {
-		protoTask := &taskpb.Task{
-			Id:          task.ID,
-			Title:       task.Title,
-			Description: task.Description,
-			UserId:      task.UserID,
+		protoTasks = append(protoTasks, toProtoTask(task))
+	}
+
+	return &taskpb.ListTasksResponse{
+		Tasks:         protoTasks,
+		Message:       "Tasks list fetched successfully",
+		NextPageToken: next,
+	}, nil
+}
+
+func (h *TaskHandler) GetTask(ctx context.Context, request *taskpb.GetTaskRequest) (*taskpb.GetTaskResponse, error) {
+	actor, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := h.uc.Get(ctx, actor, request.GetId())
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &taskpb.GetTaskResponse{Task: toProtoTask(task)}, nil
+}
+
+func (h *TaskHandler) UpdateTask(ctx context.Context, request *taskpb.UpdateTaskRequest) (*taskpb.UpdateTaskResponse, error) {
+	actor, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := request.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		paths = []string{"title", "description"}
+	}
+
+	var upd domain.TaskUpdate
+	for _, path := range paths {
+		switch path {
+		case "title":
+			if request.GetTitle() == "" {
+				return nil, apperr.ToStatus(apperr.Required("title"))
+			}
+			title := request.GetTitle()
+			upd.Title = &title
+		case "description":
+			description := request.GetDescription()
+			upd.Description = &description
+		default:
+			return nil, apperr.ToStatus(apperr.InvalidField("update_mask", "UNKNOWN_FIELD", fmt.Sprintf("unknown field in update_mask: %q", path)))
 		}
+	}
 
-		protoTasks = append(protoTasks, protoTask)
+	task, err := h.uc.Update(ctx, actor, request.GetId(), upd)
+	if err != nil {
+		return nil, apperr.ToStatus(err)
 	}
 
+	return &taskpb.UpdateTaskResponse{Task: toProtoTask(task)}, nil
+}
+
+func (h *TaskHandler) DeleteTask(ctx context.Context, request *taskpb.DeleteTaskRequest) (*taskpb.DeleteTaskResponse, error) {
+	actor, err := currentUser(ctx)
 	if err != nil {
-		return nil, errors.New("can not fetch tasks list")
+		return nil, err
 	}
 
-	return &taskpb.ListTasksResponse{
-		Tasks:   protoTasks,
-		Message: "Tasks list fetched successfully",
-	}, nil
+	if err := h.uc.Delete(ctx, actor, request.GetId()); err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &taskpb.DeleteTaskResponse{Message: "Task deleted successfully"}, nil
+}
+
+func (h *TaskHandler) TransitionTask(ctx context.Context, request *taskpb.TransitionTaskRequest) (*taskpb.TransitionTaskResponse, error) {
+	actor, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := h.uc.Transition(ctx, actor, request.GetId(), domain.Status(request.GetStatus()))
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &taskpb.TransitionTaskResponse{Task: toProtoTask(task)}, nil
+}
+
+func (h *TaskHandler) AssignTask(ctx context.Context, request *taskpb.AssignTaskRequest) (*taskpb.AssignTaskResponse, error) {
+	actor, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var task *domain.Task
+	if request.GetAssigneeId() != 0 {
+		task, err = h.uc.Assign(ctx, actor, request.GetId(), request.GetAssigneeId())
+	} else {
+		team := make([]domain.Member, 0, len(request.GetTeam()))
+		for _, m := range request.GetTeam() {
+			team = append(team, domain.Member{UserID: m.GetUserId(), Capacity: int(m.GetCapacity())})
+		}
+		task, err = h.uc.AutoAssign(ctx, actor, request.GetId(), strategies[request.GetStrategy()], team)
+	}
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &taskpb.AssignTaskResponse{Task: toProtoTask(task)}, nil
+}
+
+var strategies = map[taskpb.DistributionStrategy]domain.Strategy{
+	taskpb.DistributionStrategy_DISTRIBUTION_STRATEGY_ROUND_ROBIN:  domain.StrategyRoundRobin,
+	taskpb.DistributionStrategy_DISTRIBUTION_STRATEGY_LEAST_LOADED: domain.StrategyLeastLoaded,
+	taskpb.DistributionStrategy_DISTRIBUTION_STRATEGY_WEIGHTED:     domain.StrategyWeighted,
+}
+
+func toProtoTask(task *domain.Task) *taskpb.Task {
+	return &taskpb.Task{
+		Id:               task.ID,
+		WorkspaceId:      task.WorkspaceID,
+		Title:            task.Title,
+		Description:      task.Description,
+		UserId:           task.UserID,
+		Status:           string(task.Status),
+		AssigneeId:       task.AssigneeID,
+		AssignmentReason: task.AssignmentReason,
+		CreatedAt:        timestamppb.New(task.CreatedAt),
+		UpdatedAt:        timestamppb.New(task.UpdatedAt),
+	}
 }
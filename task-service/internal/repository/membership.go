@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
+	"github.com/Murodkadirkhanoff/taqsym.uz/task-service/internal/domain"
+)
+
+// membership спрашивает участников пространства у user-service. Вызов идёт
+// от имени пользователя из ctx: его личность заново подписывает
+// grpcauth.UnaryClientInterceptor клиента.
+type membership struct {
+	client authpb.AuthServiceClient
+}
+
+func NewMembership(client authpb.AuthServiceClient) domain.Membership {
+	return &membership{client: client}
+}
+
+func (m *membership) Members(ctx context.Context, workspaceID int64) (map[int64]bool, error) {
+	resp, err := m.client.ListWorkspaceMembers(ctx, &authpb.ListWorkspaceMembersRequest{WorkspaceId: workspaceID})
+	if err != nil {
+		return nil, err
+	}
+	members := make(map[int64]bool, len(resp.Members))
+	for _, mem := range resp.Members {
+		members[mem.UserId] = true
+	}
+	return members, nil
+}
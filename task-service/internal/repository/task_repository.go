@@ -3,8 +3,12 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/Murodkadirkhanoff/taqsym.uz/task-service/internal/domain"
+	"github.com/lib/pq"
 )
 
 type TaskRepository struct {
@@ -15,15 +19,103 @@ func NewTaskRepository(db *sql.DB) domain.Repository {
 	return &TaskRepository{db: db}
 }
 
+const taskColumns = "id, workspace_id, title, description, user_id, status, COALESCE(assignee_id, 0), assignment_reason, created_at, updated_at"
+
+// sortColumns сопоставляет поле сортировки с колонкой и её типом для курсора.
+var sortColumns = map[domain.SortField]struct{ column, cast string }{
+	domain.SortByCreatedAt: {"created_at", "timestamptz"},
+	domain.SortByUpdatedAt: {"updated_at", "timestamptz"},
+	domain.SortByTitle:     {"title", "text"},
+	domain.SortByID:        {"id", "bigint"},
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row scanner) (*domain.Task, error) {
+	var task domain.Task
+	err := row.Scan(&task.ID, &task.WorkspaceID, &task.Title, &task.Description, &task.UserID, &task.Status,
+		&task.AssigneeID, &task.AssignmentReason, &task.CreatedAt, &task.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
 func (r *TaskRepository) Create(ctx context.Context, task *domain.Task) error {
 	err := r.db.QueryRowContext(ctx,
-		"INSERT INTO tasks (title, description, user_id) VALUES ($1, $2, $3) RETURNING id",
-		task.Title, task.Description, task.UserID).Scan(&task.ID)
+		"INSERT INTO tasks (workspace_id, title, description, user_id, status) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at",
+		task.WorkspaceID, task.Title, task.Description, task.UserID, task.Status).Scan(&task.ID, &task.CreatedAt, &task.UpdatedAt)
 	return err
 }
 
-func (r *TaskRepository) List(ctx context.Context) ([]*domain.Task, error) {
-	rows, err := r.db.QueryContext(ctx, "SELECT id, title, description, user_id FROM tasks")
+func (r *TaskRepository) List(ctx context.Context, f domain.TaskFilter) ([]*domain.Task, error) {
+	sort, ok := sortColumns[f.SortBy]
+	if !ok {
+		return nil, domain.ErrInvalidFilter
+	}
+
+	var (
+		where []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	where = append(where, "workspace_id = "+arg(f.WorkspaceID))
+	if f.VisibleTo != 0 {
+		p := arg(f.VisibleTo)
+		where = append(where, "(user_id = "+p+" OR assignee_id = "+p+")")
+	}
+	if f.AuthorID != 0 {
+		where = append(where, "user_id = "+arg(f.AuthorID))
+	}
+	if f.AssigneeID != 0 {
+		where = append(where, "assignee_id = "+arg(f.AssigneeID))
+	}
+	if len(f.Statuses) > 0 {
+		statuses := make([]string, 0, len(f.Statuses))
+		for _, st := range f.Statuses {
+			statuses = append(statuses, string(st))
+		}
+		where = append(where, "status = ANY("+arg(pq.Array(statuses))+")")
+	}
+	if !f.CreatedAfter.IsZero() {
+		where = append(where, "created_at >= "+arg(f.CreatedAfter))
+	}
+	if !f.CreatedBefore.IsZero() {
+		where = append(where, "created_at < "+arg(f.CreatedBefore))
+	}
+	if f.Query != "" {
+		p := arg("%" + likeEscaper.Replace(f.Query) + "%")
+		where = append(where, "(title ILIKE "+p+" OR description ILIKE "+p+")")
+	}
+
+	op, dir := ">", "ASC"
+	if f.Desc {
+		op, dir = "<", "DESC"
+	}
+	if f.After != nil {
+		if f.SortBy == domain.SortByID {
+			where = append(where, "id "+op+" "+arg(f.After.ID))
+		} else {
+			where = append(where, fmt.Sprintf("(%s, id) %s (%s::%s, %s)",
+				sort.column, op, arg(f.After.Value), sort.cast, arg(f.After.ID)))
+		}
+	}
+
+	query := "SELECT " + taskColumns + " FROM tasks WHERE " + strings.Join(where, " AND ")
+	if f.SortBy == domain.SortByID {
+		query += " ORDER BY id " + dir
+	} else {
+		query += fmt.Sprintf(" ORDER BY %s %s, id %s", sort.column, dir, dir)
+	}
+	query += " LIMIT " + arg(f.Limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -31,12 +123,146 @@ func (r *TaskRepository) List(ctx context.Context) ([]*domain.Task, error) {
 
 	var tasks []*domain.Task
 	for rows.Next() {
-		var task domain.Task
-		err := rows.Scan(&task.ID, &task.Title, &task.Description, &task.UserID)
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (r *TaskRepository) GetByID(ctx context.Context, workspaceID, id int64) (*domain.Task, error) {
+	row := r.db.QueryRowContext(ctx,
+		"SELECT "+taskColumns+" FROM tasks WHERE workspace_id = $1 AND id = $2", workspaceID, id)
+
+	task, err := scanTask(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) error {
+	err := r.db.QueryRowContext(ctx,
+		"UPDATE tasks SET title = $1, description = $2, updated_at = now() WHERE workspace_id = $3 AND id = $4 RETURNING updated_at",
+		task.Title, task.Description, task.WorkspaceID, task.ID).Scan(&task.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrTaskNotFound
+	}
+	return err
+}
+
+func (r *TaskRepository) Delete(ctx context.Context, workspaceID, id int64) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM tasks WHERE workspace_id = $1 AND id = $2", workspaceID, id)
+	if err != nil {
+		return err
+	}
+	return checkAffected(res)
+}
+
+func (r *TaskRepository) UpdateStatus(ctx context.Context, workspaceID, id int64, from, to domain.Status) error {
+	res, err := r.db.ExecContext(ctx,
+		"UPDATE tasks SET status = $1, updated_at = now() WHERE workspace_id = $2 AND id = $3 AND status = $4",
+		to, workspaceID, id, from)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		// задачу успели удалить или поменять её статус параллельно
+		var exists bool
+		err := r.db.QueryRowContext(ctx,
+			"SELECT EXISTS (SELECT 1 FROM tasks WHERE workspace_id = $1 AND id = $2)",
+			workspaceID, id).Scan(&exists)
 		if err != nil {
+			return err
+		}
+		if !exists {
+			return domain.ErrTaskNotFound
+		}
+		return domain.ErrInvalidTransition
+	}
+	return nil
+}
+
+func (r *TaskRepository) Assign(ctx context.Context, a *domain.Assignment) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		"UPDATE tasks SET assignee_id = $1, assignment_reason = $2, updated_at = now() WHERE workspace_id = $3 AND id = $4",
+		a.AssigneeID, a.Reason, a.WorkspaceID, a.TaskID)
+	if err != nil {
+		return err
+	}
+	if err := checkAffected(res); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO task_assignments (task_id, assignee_id, assigned_by, strategy, reason) VALUES ($1, $2, $3, $4, $5)",
+		a.TaskID, a.AssigneeID, a.AssignedBy, a.Strategy, a.Reason)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *TaskRepository) OpenTaskCounts(ctx context.Context, workspaceID int64, userIDs []int64) (map[int64]int, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT assignee_id, COUNT(*) FROM tasks WHERE workspace_id = $1 AND assignee_id = ANY($2) AND status NOT IN ($3, $4) GROUP BY assignee_id",
+		workspaceID, pq.Array(userIDs), domain.StatusDone, domain.StatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]int, len(userIDs))
+	for rows.Next() {
+		var (
+			userID int64
+			count  int
+		)
+		if err := rows.Scan(&userID, &count); err != nil {
 			return nil, err
 		}
-		tasks = append(tasks, &task)
+		counts[userID] = count
+	}
+	return counts, rows.Err()
+}
+
+func (r *TaskRepository) LastAssignee(ctx context.Context, workspaceID int64, strategy domain.Strategy, userIDs []int64) (int64, error) {
+	var userID int64
+	err := r.db.QueryRowContext(ctx,
+		`SELECT a.assignee_id FROM task_assignments a JOIN tasks t ON t.id = a.task_id
+		WHERE t.workspace_id = $1 AND a.strategy = $2 AND a.assignee_id = ANY($3) ORDER BY a.id DESC LIMIT 1`,
+		workspaceID, strategy, pq.Array(userIDs)).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return userID, err
+}
+
+func checkAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrTaskNotFound
 	}
-	return tasks, nil
+	return nil
 }
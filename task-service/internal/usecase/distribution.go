@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/task-service/internal/domain"
+)
+
+// pickRoundRobin выбирает участника, следующего за последним назначенным.
+func pickRoundRobin(team []domain.Member, last int64) (domain.Member, string) {
+	next := 0
+	for i, m := range team {
+		if m.UserID == last {
+			next = (i + 1) % len(team)
+			break
+		}
+	}
+	m := team[next]
+	if last == 0 {
+		return m, fmt.Sprintf("round_robin: first in team, user %d", m.UserID)
+	}
+	return m, fmt.Sprintf("round_robin: next after user %d", last)
+}
+
+// pickLeastLoaded выбирает участника с наименьшим числом открытых задач.
+// При равенстве побеждает тот, кто раньше в списке команды.
+func pickLeastLoaded(team []domain.Member, load map[int64]int) (domain.Member, string) {
+	best := team[0]
+	for _, m := range team[1:] {
+		if load[m.UserID] < load[best.UserID] {
+			best = m
+		}
+	}
+	return best, fmt.Sprintf("least_loaded: %d open tasks, fewest in team of %d", load[best.UserID], len(team))
+}
+
+// pickWeighted выбирает участника с наименьшей загрузкой относительно capacity.
+// Участники, у которых загрузка уже достигла capacity, пропускаются.
+func pickWeighted(team []domain.Member, load map[int64]int) (domain.Member, string, error) {
+	var (
+		best      domain.Member
+		bestRatio float64
+		found     bool
+	)
+	for _, m := range team {
+		if m.Capacity <= 0 || load[m.UserID] >= m.Capacity {
+			continue
+		}
+		ratio := float64(load[m.UserID]) / float64(m.Capacity)
+		if !found || ratio < bestRatio {
+			best, bestRatio, found = m, ratio, true
+		}
+	}
+	if !found {
+		return domain.Member{}, "", fmt.Errorf("%w: every team member is at capacity", domain.ErrInvalidAssignment)
+	}
+	return best, fmt.Sprintf("weighted: %d/%d open tasks, lowest utilisation in team", load[best.UserID], best.Capacity), nil
+}
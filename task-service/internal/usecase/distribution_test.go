@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/task-service/internal/domain"
+)
+
+func team(capacities ...int) []domain.Member {
+	members := make([]domain.Member, len(capacities))
+	for i, c := range capacities {
+		members[i] = domain.Member{UserID: int64(i + 1), Capacity: c}
+	}
+	return members
+}
+
+func TestPickRoundRobin(t *testing.T) {
+	tests := []struct {
+		name string
+		last int64
+		want int64
+	}{
+		{"first assignment", 0, 1},
+		{"next in team", 1, 2},
+		{"wraps around", 3, 1},
+		{"last left the team", 9, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := pickRoundRobin(team(0, 0, 0), tt.last)
+			if got.UserID != tt.want {
+				t.Errorf("picked %d, want %d (%s)", got.UserID, tt.want, reason)
+			}
+		})
+	}
+}
+
+func TestPickLeastLoaded(t *testing.T) {
+	tests := []struct {
+		name string
+		load map[int64]int
+		want int64
+	}{
+		{"no load", nil, 1},
+		{"fewest open tasks", map[int64]int{1: 3, 2: 1, 3: 2}, 2},
+		{"tie goes to first", map[int64]int{1: 2, 2: 1, 3: 1}, 2},
+		{"missing member counts as idle", map[int64]int{1: 1, 2: 1}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := pickLeastLoaded(team(0, 0, 0), tt.load)
+			if got.UserID != tt.want {
+				t.Errorf("picked %d, want %d (%s)", got.UserID, tt.want, reason)
+			}
+		})
+	}
+}
+
+func TestPickWeighted(t *testing.T) {
+	tests := []struct {
+		name    string
+		team    []domain.Member
+		load    map[int64]int
+		want    int64
+		wantErr bool
+	}{
+		{"lowest utilisation", team(10, 2, 4), map[int64]int{1: 5, 2: 1, 3: 1}, 3, false},
+		{"skips full members", team(2, 5), map[int64]int{1: 0, 2: 5}, 1, false},
+		{"skips zero capacity", team(0, 3), map[int64]int{2: 2}, 2, false},
+		{"tie goes to first", team(4, 2), map[int64]int{1: 2, 2: 1}, 1, false},
+		{"everyone at capacity", team(1, 2), map[int64]int{1: 1, 2: 3}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason, err := pickWeighted(tt.team, tt.load)
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrInvalidAssignment) {
+					t.Errorf("err = %v, want ErrInvalidAssignment", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("pickWeighted: %v", err)
+			}
+			if got.UserID != tt.want {
+				t.Errorf("picked %d, want %d (%s)", got.UserID, tt.want, reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/task-service/internal/domain"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// pageToken — содержимое непрозрачного next_page_token.
+// Сортировка хранится в токене, чтобы его нельзя было применить к другой выборке.
+type pageToken struct {
+	SortBy domain.SortField `json:"s"`
+	Desc   bool             `json:"d,omitempty"`
+	Value  string           `json:"v"`
+	ID     int64            `json:"i"`
+}
+
+func encodePageToken(f domain.TaskFilter, last *domain.Task) string {
+	t := pageToken{SortBy: f.SortBy, Desc: f.Desc, Value: sortValue(f.SortBy, last), ID: last.ID}
+	b, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodePageToken(token string, f domain.TaskFilter) (*domain.Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed page token", domain.ErrInvalidFilter)
+	}
+	var t pageToken
+	if err := json.Unmarshal(b, &t); err != nil {
+		return nil, fmt.Errorf("%w: malformed page token", domain.ErrInvalidFilter)
+	}
+	if t.SortBy != f.SortBy || t.Desc != f.Desc {
+		return nil, fmt.Errorf("%w: page token does not match order_by", domain.ErrInvalidFilter)
+	}
+	// значение уходит в запрос с приведением типа: подделанный токен
+	// должен давать ошибку запроса, а не ошибку базы
+	if !validSortValue(t.SortBy, t.Value) || t.ID <= 0 {
+		return nil, fmt.Errorf("%w: malformed page token", domain.ErrInvalidFilter)
+	}
+	return &domain.Cursor{Value: t.Value, ID: t.ID}, nil
+}
+
+func sortValue(field domain.SortField, task *domain.Task) string {
+	switch field {
+	case domain.SortByCreatedAt:
+		return task.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case domain.SortByUpdatedAt:
+		return task.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	case domain.SortByTitle:
+		return task.Title
+	default:
+		return strconv.FormatInt(task.ID, 10)
+	}
+}
+
+func validSortValue(field domain.SortField, value string) bool {
+	switch field {
+	case domain.SortByCreatedAt, domain.SortByUpdatedAt:
+		_, err := time.Parse(time.RFC3339Nano, value)
+		return err == nil
+	case domain.SortByTitle:
+		return true
+	default:
+		_, err := strconv.ParseInt(value, 10, 64)
+		return err == nil
+	}
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/task-service/internal/domain"
+)
+
+func TestPageTokenRoundTrip(t *testing.T) {
+	created := time.Date(2024, 3, 1, 10, 30, 0, 123456789, time.FixedZone("UTC+5", 5*3600))
+	task := &domain.Task{ID: 42, Title: "Отчёт, квартал", CreatedAt: created, UpdatedAt: created.Add(time.Hour)}
+
+	tests := []struct {
+		name   string
+		filter domain.TaskFilter
+		value  string
+	}{
+		{"created_at desc", domain.TaskFilter{SortBy: domain.SortByCreatedAt, Desc: true}, "2024-03-01T05:30:00.123456789Z"},
+		{"updated_at", domain.TaskFilter{SortBy: domain.SortByUpdatedAt}, "2024-03-01T06:30:00.123456789Z"},
+		{"title", domain.TaskFilter{SortBy: domain.SortByTitle}, "Отчёт, квартал"},
+		{"id desc", domain.TaskFilter{SortBy: domain.SortByID, Desc: true}, "42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := encodePageToken(tt.filter, task)
+			cursor, err := decodePageToken(token, tt.filter)
+			if err != nil {
+				t.Fatalf("decodePageToken: %v", err)
+			}
+			if cursor.Value != tt.value || cursor.ID != task.ID {
+				t.Errorf("cursor = %+v, want {Value:%s ID:%d}", *cursor, tt.value, task.ID)
+			}
+		})
+	}
+}
+
+func TestDecodePageTokenRejects(t *testing.T) {
+	byCreated := domain.TaskFilter{SortBy: domain.SortByCreatedAt, Desc: true}
+	valid := encodePageToken(byCreated, &domain.Task{ID: 7, CreatedAt: time.Now()})
+	raw := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name   string
+		token  string
+		filter domain.TaskFilter
+	}{
+		{"not base64", "%%%", byCreated},
+		{"not json", raw("not json"), byCreated},
+		{"other sort field", valid, domain.TaskFilter{SortBy: domain.SortByTitle, Desc: true}},
+		{"other direction", valid, domain.TaskFilter{SortBy: domain.SortByCreatedAt}},
+		{"tampered sort key", raw(`{"s":"title","d":true,"v":"a","i":7}`), byCreated},
+		{"tampered time", raw(`{"s":"created_at","d":true,"v":"yesterday","i":7}`), byCreated},
+		{"tampered id value", raw(`{"s":"id","v":"1 OR 1=1","i":7}`), domain.TaskFilter{SortBy: domain.SortByID}},
+		{"missing id", raw(`{"s":"title","v":"a"}`), domain.TaskFilter{SortBy: domain.SortByTitle}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodePageToken(tt.token, tt.filter)
+			if !errors.Is(err, domain.ErrInvalidFilter) {
+				t.Errorf("err = %v, want ErrInvalidFilter", err)
+			}
+		})
+	}
+}
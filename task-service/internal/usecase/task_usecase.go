@@ -2,23 +2,256 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/Murodkadirkhanoff/taqsym.uz/task-service/internal/domain"
 )
 
 type taskUsecase struct {
-	repo domain.Repository
+	repo        domain.Repository
+	members     domain.Membership
+	transitions domain.Transitions
 }
 
-func NewTaskUsecase(repo domain.Repository) domain.Usecase {
-	return &taskUsecase{repo: repo}
+func NewTaskUsecase(repo domain.Repository, members domain.Membership, transitions domain.Transitions) domain.Usecase {
+	return &taskUsecase{repo: repo, members: members, transitions: transitions}
 }
 
 func (uc *taskUsecase) Create(ctx context.Context, task *domain.Task) error {
+	if task.WorkspaceID == 0 {
+		return domain.ErrNoWorkspace
+	}
+	task.Status = domain.StatusTodo
 	err := uc.repo.Create(ctx, task)
 	return err
 }
 
-func (uc *taskUsecase) List(ctx context.Context) ([]*domain.Task, error) {
-	return uc.repo.List(ctx)
+func (uc *taskUsecase) List(ctx context.Context, filter domain.TaskFilter, pageToken string) ([]*domain.Task, string, error) {
+	if filter.WorkspaceID == 0 {
+		return nil, "", domain.ErrNoWorkspace
+	}
+	if filter.SortBy == "" {
+		filter.SortBy, filter.Desc = domain.SortByCreatedAt, true
+	}
+	switch filter.SortBy {
+	case domain.SortByCreatedAt, domain.SortByUpdatedAt, domain.SortByTitle, domain.SortByID:
+	default:
+		return nil, "", fmt.Errorf("%w: unknown order_by %q", domain.ErrInvalidFilter, filter.SortBy)
+	}
+	for _, st := range filter.Statuses {
+		if !st.Valid() {
+			return nil, "", fmt.Errorf("%w: %q", domain.ErrInvalidStatus, st)
+		}
+	}
+
+	switch {
+	case filter.Limit < 0:
+		return nil, "", fmt.Errorf("%w: negative page_size", domain.ErrInvalidFilter)
+	case filter.Limit == 0:
+		filter.Limit = defaultPageSize
+	case filter.Limit > maxPageSize:
+		filter.Limit = maxPageSize
+	}
+
+	if pageToken != "" {
+		cursor, err := decodePageToken(pageToken, filter)
+		if err != nil {
+			return nil, "", err
+		}
+		filter.After = cursor
+	}
+
+	// берём на одну задачу больше, чтобы понять, есть ли следующая страница
+	pageSize := filter.Limit
+	filter.Limit++
+	tasks, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(tasks) > pageSize {
+		tasks = tasks[:pageSize]
+		next = encodePageToken(filter, tasks[pageSize-1])
+	}
+	return tasks, next, nil
+}
+
+// Get возвращает задачу, если пользователь её автор или исполнитель.
+// Редактировать и переводить по статусам задачу могут они же.
+func (uc *taskUsecase) Get(ctx context.Context, actor domain.Actor, id int64) (*domain.Task, error) {
+	if actor.WorkspaceID == 0 {
+		return nil, domain.ErrNoWorkspace
+	}
+	task, err := uc.repo.GetByID(ctx, actor.WorkspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !task.CanView(actor.UserID) {
+		return nil, domain.ErrForbidden
+	}
+	return task, nil
+}
+
+// getOwned возвращает задачу, только если пользователь её автор.
+// Удалять и переназначать задачу может только автор.
+func (uc *taskUsecase) getOwned(ctx context.Context, actor domain.Actor, id int64) (*domain.Task, error) {
+	if actor.WorkspaceID == 0 {
+		return nil, domain.ErrNoWorkspace
+	}
+	task, err := uc.repo.GetByID(ctx, actor.WorkspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !task.IsOwner(actor.UserID) {
+		return nil, domain.ErrForbidden
+	}
+	return task, nil
+}
+
+func (uc *taskUsecase) Update(ctx context.Context, actor domain.Actor, id int64, upd domain.TaskUpdate) (*domain.Task, error) {
+	task, err := uc.Get(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if upd.Title != nil {
+		task.Title = *upd.Title
+	}
+	if upd.Description != nil {
+		task.Description = *upd.Description
+	}
+
+	if err := uc.repo.Update(ctx, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (uc *taskUsecase) Delete(ctx context.Context, actor domain.Actor, id int64) error {
+	if _, err := uc.getOwned(ctx, actor, id); err != nil {
+		return err
+	}
+	return uc.repo.Delete(ctx, actor.WorkspaceID, id)
+}
+
+func (uc *taskUsecase) Transition(ctx context.Context, actor domain.Actor, id int64, to domain.Status) (*domain.Task, error) {
+	if !to.Valid() {
+		return nil, domain.ErrInvalidStatus
+	}
+
+	task, err := uc.Get(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !uc.transitions.Allowed(task.Status, to) {
+		return nil, fmt.Errorf("%w: %s -> %s", domain.ErrInvalidTransition, task.Status, to)
+	}
+
+	if err := uc.repo.UpdateStatus(ctx, actor.WorkspaceID, id, task.Status, to); err != nil {
+		return nil, err
+	}
+	task.Status = to
+	return task, nil
+}
+
+func (uc *taskUsecase) Assign(ctx context.Context, actor domain.Actor, id, assigneeID int64) (*domain.Task, error) {
+	if assigneeID <= 0 {
+		return nil, fmt.Errorf("%w: assignee_id is required", domain.ErrInvalidAssignment)
+	}
+
+	task, err := uc.getOwned(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkMembers(ctx, actor.WorkspaceID, []int64{assigneeID}); err != nil {
+		return nil, err
+	}
+
+	return uc.assign(ctx, task, &domain.Assignment{
+		WorkspaceID: actor.WorkspaceID,
+		TaskID:      id,
+		AssigneeID:  assigneeID,
+		AssignedBy:  actor.UserID,
+		Strategy:    domain.StrategyManual,
+		Reason:      fmt.Sprintf("manual: assigned by user %d", actor.UserID),
+	})
+}
+
+func (uc *taskUsecase) AutoAssign(ctx context.Context, actor domain.Actor, id int64, strategy domain.Strategy, team []domain.Member) (*domain.Task, error) {
+	if len(team) == 0 {
+		return nil, fmt.Errorf("%w: team is empty", domain.ErrInvalidAssignment)
+	}
+
+	task, err := uc.getOwned(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(team))
+	for _, m := range team {
+		ids = append(ids, m.UserID)
+	}
+	if err := uc.checkMembers(ctx, actor.WorkspaceID, ids); err != nil {
+		return nil, err
+	}
+
+	var (
+		picked domain.Member
+		reason string
+	)
+	switch strategy {
+	case domain.StrategyRoundRobin:
+		last, err := uc.repo.LastAssignee(ctx, actor.WorkspaceID, strategy, ids)
+		if err != nil {
+			return nil, err
+		}
+		picked, reason = pickRoundRobin(team, last)
+	case domain.StrategyLeastLoaded, domain.StrategyWeighted:
+		load, err := uc.repo.OpenTaskCounts(ctx, actor.WorkspaceID, ids)
+		if err != nil {
+			return nil, err
+		}
+		if strategy == domain.StrategyLeastLoaded {
+			picked, reason = pickLeastLoaded(team, load)
+		} else if picked, reason, err = pickWeighted(team, load); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown strategy %q", domain.ErrInvalidAssignment, strategy)
+	}
+
+	return uc.assign(ctx, task, &domain.Assignment{
+		WorkspaceID: actor.WorkspaceID,
+		TaskID:      id,
+		AssigneeID:  picked.UserID,
+		AssignedBy:  actor.UserID,
+		Strategy:    strategy,
+		Reason:      reason,
+	})
+}
+
+// checkMembers проверяет, что все userIDs состоят в пространстве: назначить
+// задачу можно только его участнику.
+func (uc *taskUsecase) checkMembers(ctx context.Context, workspaceID int64, userIDs []int64) error {
+	members, err := uc.members.Members(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	for _, id := range userIDs {
+		if !members[id] {
+			return fmt.Errorf("%w: user %d", domain.ErrNotWorkspaceMember, id)
+		}
+	}
+	return nil
+}
+
+func (uc *taskUsecase) assign(ctx context.Context, task *domain.Task, a *domain.Assignment) (*domain.Task, error) {
+	if err := uc.repo.Assign(ctx, a); err != nil {
+		return nil, err
+	}
+	task.AssigneeID = a.AssigneeID
+	task.AssignmentReason = a.Reason
+	return task, nil
 }
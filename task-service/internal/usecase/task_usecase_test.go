@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/task-service/internal/domain"
+)
+
+type fakeMembership map[int64]bool
+
+func (f fakeMembership) Members(context.Context, int64) (map[int64]bool, error) {
+	return f, nil
+}
+
+func TestCheckMembers(t *testing.T) {
+	uc := &taskUsecase{members: fakeMembership{1: true, 2: true}}
+
+	tests := []struct {
+		name    string
+		ids     []int64
+		wantErr bool
+	}{
+		{"all members", []int64{1, 2}, false},
+		{"one outsider", []int64{1, 3}, true},
+		{"outsider alone", []int64{3}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := uc.checkMembers(context.Background(), 10, tt.ids)
+			if tt.wantErr != errors.Is(err, domain.ErrNotWorkspaceMember) {
+				t.Errorf("checkMembers(%v) = %v", tt.ids, err)
+			}
+		})
+	}
+}
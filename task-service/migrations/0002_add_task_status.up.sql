@@ -0,0 +1,5 @@
+ALTER TABLE tasks
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'todo'
+        CHECK (status IN ('todo', 'in_progress', 'review', 'done', 'cancelled'));
+
+CREATE INDEX idx_tasks_status ON tasks (status);
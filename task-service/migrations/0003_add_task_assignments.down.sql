@@ -0,0 +1,5 @@
+DROP TABLE task_assignments;
+DROP INDEX IF EXISTS idx_tasks_assignee_status;
+ALTER TABLE tasks
+    DROP COLUMN assignment_reason,
+    DROP COLUMN assignee_id;
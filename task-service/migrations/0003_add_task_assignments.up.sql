@@ -0,0 +1,18 @@
+ALTER TABLE tasks
+    ADD COLUMN assignee_id BIGINT,
+    ADD COLUMN assignment_reason TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_tasks_assignee_status ON tasks (assignee_id, status);
+
+CREATE TABLE task_assignments (
+    id SERIAL PRIMARY KEY,
+    task_id BIGINT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    assignee_id BIGINT NOT NULL,
+    assigned_by BIGINT NOT NULL,
+    strategy TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX idx_task_assignments_task_id ON task_assignments (task_id);
+CREATE INDEX idx_task_assignments_strategy ON task_assignments (strategy, id);
@@ -0,0 +1,11 @@
+UPDATE tasks SET created_at = now() WHERE created_at IS NULL;
+UPDATE tasks SET updated_at = created_at WHERE updated_at IS NULL;
+
+ALTER TABLE tasks
+    ALTER COLUMN created_at SET NOT NULL,
+    ALTER COLUMN updated_at SET NOT NULL;
+
+CREATE INDEX idx_tasks_created_at_id ON tasks (created_at, id);
+CREATE INDEX idx_tasks_updated_at_id ON tasks (updated_at, id);
+CREATE INDEX idx_tasks_title_id ON tasks (title, id);
+CREATE INDEX idx_tasks_user_id ON tasks (user_id);
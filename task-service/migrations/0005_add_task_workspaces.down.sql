@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_tasks_workspace_assignee_id;
+DROP INDEX IF EXISTS idx_tasks_workspace_user_id;
+DROP INDEX IF EXISTS idx_tasks_workspace_title_id;
+DROP INDEX IF EXISTS idx_tasks_workspace_updated_at_id;
+DROP INDEX IF EXISTS idx_tasks_workspace_created_at_id;
+
+CREATE INDEX idx_tasks_created_at_id ON tasks (created_at, id);
+CREATE INDEX idx_tasks_updated_at_id ON tasks (updated_at, id);
+CREATE INDEX idx_tasks_title_id ON tasks (title, id);
+CREATE INDEX idx_tasks_user_id ON tasks (user_id);
+
+ALTER TABLE tasks DROP COLUMN workspace_id;
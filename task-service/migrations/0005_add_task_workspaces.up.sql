@@ -0,0 +1,15 @@
+-- Задачи, созданные до появления пространств, попадают в workspace 0,
+-- недоступный ни одному пользователю. Перенесите их вручную, если нужно.
+ALTER TABLE tasks ADD COLUMN workspace_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE tasks ALTER COLUMN workspace_id DROP DEFAULT;
+
+DROP INDEX IF EXISTS idx_tasks_user_id;
+DROP INDEX IF EXISTS idx_tasks_title_id;
+DROP INDEX IF EXISTS idx_tasks_updated_at_id;
+DROP INDEX IF EXISTS idx_tasks_created_at_id;
+
+CREATE INDEX idx_tasks_workspace_created_at_id ON tasks (workspace_id, created_at, id);
+CREATE INDEX idx_tasks_workspace_updated_at_id ON tasks (workspace_id, updated_at, id);
+CREATE INDEX idx_tasks_workspace_title_id ON tasks (workspace_id, title, id);
+CREATE INDEX idx_tasks_workspace_user_id ON tasks (workspace_id, user_id);
+CREATE INDEX idx_tasks_workspace_assignee_id ON tasks (workspace_id, assignee_id);
@@ -3,12 +3,12 @@ package db
 import (
 	"database/sql"
 	"fmt"
-)
 
-func NewPostgres() (*sql.DB, error) {
-	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		"postgres", "password", "task-db", "5432", "postgres")
+	_ "github.com/lib/pq"
+)
 
+// NewPostgres открывает соединение с PostgreSQL по dsn и проверяет его.
+func NewPostgres(dsn string) (*sql.DB, error) {
 	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка открытия БД: %w", err)
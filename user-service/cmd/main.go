@@ -1,30 +1,115 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
+	"os"
+	"sync"
+	"time"
 
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/grpcauth"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/healthcheck"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/jwks"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/migrate"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/ratelimit"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/revocation"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/shutdown"
 	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/config"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
 	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/handler"
 	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/repository"
 	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/usecase"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/migrations"
 	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/pkg/db"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/pkg/mailer"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/pkg/secretbox"
 	_ "github.com/lib/pq"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
 
 func main() {
-	database, err := db.NewPostgres()
+	// `user-service migrate up|down [N]|status|version` управляет схемой и завершается
+	args := os.Args[1:]
+	var migrateCmd *migrate.Command
+	if len(args) > 0 && args[0] == "migrate" {
+		cmd, rest, err := migrate.ParseCommand(args[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		migrateCmd, args = &cmd, rest
+	}
+
+	cfg, err := config.Load(args)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := shutdown.Context()
+	defer stop()
+
+	database, err := db.NewPostgres(cfg.Database.DSN())
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer database.Close()
 	log.Println("Успешное подключение к базе данных!")
 
+	migrator, err := migrate.New(database, migrations.FS, "user-service")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if migrateCmd != nil {
+		if err := migrateCmd.Run(ctx, migrator, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if cfg.AutoMigrate {
+		n, err := migrator.Up(ctx)
+		if err != nil {
+			log.Fatalf("не удалось применить миграции: %v", err)
+		}
+		log.Printf("применено миграций: %d", n)
+	}
+
 	repo := repository.NewUserRepo(database)
-	uc := usecase.NewUserUseCase(repo)
-	h := handler.NewUserHandler(uc)
+	tokens := repository.NewRefreshTokenRepo(database)
+	sessions := repository.NewSessionRepo(database)
+	redisAddr := cfg.Redis.Addr
+	revoked := revocation.New(redisAddr)
+	keys := loadSigningKeys(cfg.JWT.KeysDir, cfg.JWT.SigningKID)
+	throttle := usecase.NewLoginThrottle(
+		ratelimit.New(redisAddr, "login:account:", usecase.AccountLoginPolicy),
+		ratelimit.New(redisAddr, "login:ip:", usecase.IPLoginPolicy),
+	)
+	totpBox, err := secretbox.New(cfg.MFA.EncryptionKey)
+	if err != nil {
+		log.Fatalf("неверный mfa.encryption_key: %v", err)
+	}
+	uc := usecase.NewUserUseCase(repo, tokens, sessions, revoked, repository.NewMFARepo(database, totpBox), throttle, keys, cfg.RequireEmailVerification)
+	ws := usecase.NewWorkspaceUseCase(repository.NewWorkspaceRepo(database), repository.NewInvitationRepo(database))
+	mail, err := mailer.New(mailer.SMTPConfig{
+		Addr:     cfg.SMTP.Addr,
+		Username: cfg.SMTP.Username,
+		Password: cfg.SMTP.Password,
+		From:     cfg.Mail.From,
+	}, cfg.Mail.File)
+	if err != nil {
+		log.Fatalf("не удалось настроить отправку писем: %v", err)
+	}
+	pw := usecase.NewPasswordUseCase(repo, repository.NewPasswordResetRepo(database), sessions, revoked, mail, cfg.PasswordResetURL)
+	vf := usecase.NewVerificationUseCase(repo, repository.NewEmailVerificationRepo(database), mail, cfg.EmailVerifyURL)
+	pat := usecase.NewPersonalTokenUseCase(repo, repository.NewPersonalTokenRepo(database))
+	h := handler.NewUserHandler(uc, ws, pw, vf, pat, keys)
+	var background sync.WaitGroup
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		purgeDeletedAccounts(ctx, uc)
+	}()
 
 	// r := router.SetupRouter(h)
 
@@ -33,17 +118,87 @@ func main() {
 	// fmt.Println(port)
 	// r.Run(":" + port)
 
-	lis, err := net.Listen("tcp", ":50051")
+	lis, err := net.Listen("tcp", cfg.GRPCAddr)
 	if err != nil {
 		log.Fatalf("не удалось слушать: %v", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	identityKey := cfg.Gateway.IdentityKey
+	auth := grpcauth.Chain(
+		grpcauth.GatewayIdentity([]byte(identityKey)),
+		grpcauth.Revocable(grpcauth.JWT(keys.Keyfunc), revoked.IsRevoked),
+	)
+	public := grpcauth.WithPublicMethods(
+		authpb.AuthService_Login_FullMethodName,
+		authpb.AuthService_Register_FullMethodName,
+		authpb.AuthService_Refresh_FullMethodName,
+		authpb.AuthService_JWKS_FullMethodName,
+		authpb.AuthService_RequestPasswordReset_FullMethodName,
+		authpb.AuthService_ResetPassword_FullMethodName,
+		authpb.AuthService_VerifyEmail_FullMethodName,
+		authpb.AuthService_ResendVerification_FullMethodName,
+		authpb.AuthService_VerifySecondFactor_FullMethodName,
+		authpb.AuthService_AuthenticatePersonalToken_FullMethodName,
+	)
+	healthPublic := grpcauth.WithPublicMethods(healthcheck.Methods...)
+
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			grpcauth.UnaryClientIPInterceptor([]byte(identityKey)),
+			grpcauth.UnaryServerInterceptor(auth, public, healthPublic),
+		),
+		grpc.ChainStreamInterceptor(grpcauth.StreamServerInterceptor(auth, public, healthPublic)),
+	)
 	authpb.RegisterAuthServiceServer(grpcServer, h)
+	health := healthcheck.Register(grpcServer, database.PingContext, authpb.AuthService_ServiceDesc.ServiceName)
+	go health.Run(ctx, cfg.HealthInterval)
 	reflection.Register(grpcServer)
 
-	log.Println("AuthService запущен на порту :50051")
-	if err := grpcServer.Serve(lis); err != nil {
+	log.Printf("AuthService запущен на %s", cfg.GRPCAddr)
+	if err := shutdown.ServeGRPC(ctx, grpcServer, lis, cfg.ShutdownTimeout); err != nil {
 		log.Fatalf("ошибка запуска gRPC сервера: %v", err)
 	}
+	background.Wait()
+	log.Println("AuthService остановлен")
+}
+
+// purgeDeletedAccounts раз в час окончательно удаляет аккаунты,
+// удалённые больше AccountDeletionGrace назад. Начатое удаление
+// доводится до конца и после отмены ctx.
+func purgeDeletedAccounts(ctx context.Context, uc domain.UserUseCase) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		n, err := uc.PurgeDeleted(context.WithoutCancel(ctx))
+		if err != nil {
+			log.Printf("не удалось удалить аккаунты: %v", err)
+		} else if n > 0 {
+			log.Printf("окончательно удалено аккаунтов: %d", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// loadSigningKeys читает ключи подписи из dir. Без него генерирует
+// временный ключ — только для локальной разработки.
+func loadSigningKeys(dir, signingKID string) *jwks.KeySet {
+	if dir == "" {
+		log.Println("jwt.keys_dir не задан, используется временный ключ подписи")
+		keys, err := jwks.Generate()
+		if err != nil {
+			log.Fatalf("не удалось создать ключ подписи: %v", err)
+		}
+		return keys
+	}
+
+	keys, err := jwks.LoadDir(dir, signingKID)
+	if err != nil {
+		log.Fatalf("не удалось загрузить ключи подписи: %v", err)
+	}
+	return keys
 }
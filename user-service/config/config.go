@@ -1,35 +1,61 @@
+// Package config — настройки user-service.
 package config
 
 import (
-	"log"
+	"time"
 
-	"github.com/spf13/viper"
+	pkgconfig "github.com/Murodkadirkhanoff/taqsym.uz/pkg/config"
 )
 
 type Config struct {
-	Database DatabaseConfig `mapstructure:"Database"`
+	GRPCAddr string `mapstructure:"grpc_addr" default:":50051" usage:"адрес gRPC сервера"`
+	// ShutdownTimeout — сколько ждать завершения начатых запросов при остановке.
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout" default:"15s" usage:"ожидание запросов при остановке"`
+	// HealthInterval — как часто проверять базу для gRPC health сервиса.
+	HealthInterval time.Duration      `mapstructure:"health_interval" default:"5s" usage:"период проверки зависимостей"`
+	Database       pkgconfig.Postgres `mapstructure:"database"`
+	// AutoMigrate применяет встроенные миграции при старте сервиса.
+	AutoMigrate bool `mapstructure:"auto_migrate" usage:"применить миграции при старте"`
+	Redis       struct {
+		// Addr — Redis, общий с gateway: через него gateway узнаёт о токенах
+		// и сессиях, отозванных в user-service. Там же счётчики попыток входа.
+		Addr string `mapstructure:"addr" validate:"required" usage:"адрес Redis"`
+	} `mapstructure:"redis"`
+	Gateway struct {
+		// IdentityKey — общий с gateway ключ подписи личности пользователя.
+		IdentityKey string `mapstructure:"identity_key" validate:"required"`
+	} `mapstructure:"gateway"`
+	MFA struct {
+		// EncryptionKey шифрует секреты TOTP в базе. Смена ключа делает
+		// подключённую 2FA нерабочей, поэтому ключ хранится как пароль базы.
+		EncryptionKey string `mapstructure:"encryption_key" validate:"required" usage:"ключ шифрования секретов TOTP, 32 байта в base64"`
+	} `mapstructure:"mfa"`
+	JWT struct {
+		// KeysDir — каталог с ключами <kid>.pem; пуст — ключ генерируется при старте.
+		KeysDir    string `mapstructure:"keys_dir" usage:"каталог ключей подписи JWT"`
+		SigningKID string `mapstructure:"signing_kid" usage:"kid ключа, которым подписываются новые токены"`
+	} `mapstructure:"jwt"`
+	SMTP struct {
+		// Addr пуст — письма пишутся в mail.file или в stdout.
+		Addr     string `mapstructure:"addr" usage:"адрес SMTP сервера host:port"`
+		Username string `mapstructure:"username"`
+		Password string `mapstructure:"password"`
+	} `mapstructure:"smtp"`
+	Mail struct {
+		From string `mapstructure:"from" default:"no-reply@taqsym.uz"`
+		File string `mapstructure:"file" usage:"файл для писем, если SMTP не настроен"`
+	} `mapstructure:"mail"`
+	PasswordResetURL string `mapstructure:"password_reset_url" default:"http://localhost:8081/reset-password?token="`
+	EmailVerifyURL   string `mapstructure:"email_verify_url" default:"http://localhost:8081/verify-email?token="`
+	// RequireEmailVerification запрещает вход, пока email не подтверждён.
+	RequireEmailVerification bool `mapstructure:"require_email_verification"`
 }
 
-type DatabaseConfig struct {
-	Host     string `mapstructure:"Host"`
-	Port     int8   `mapstructure:"Port"`
-	User     string `mapstructure:"User"`
-	Password string `mapstructure:"Password"`
-	Db       string `mapstructure:"DB"`
-}
-
-var AppConfig Config
-
-func LoadConfig() {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath("../config") // ищет рядом с main.go
-
-	if err := viper.ReadInConfig(); err != nil {
-		log.Fatalf("Ошибка чтения config.yaml: %v", err)
-	}
-
-	if err := viper.Unmarshal(&AppConfig); err != nil {
-		log.Fatalf("Ошибка парсинга config.yaml: %v", err)
+// Load читает настройки из файла, окружения и флагов args.
+func Load(args []string) (*Config, error) {
+	var cfg Config
+	if err := pkgconfig.Load("user-service", &cfg, args); err != nil {
+		return nil, err
 	}
+	return &cfg, nil
 }
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+)
+
+var (
+	ErrInvalidInvitation  = apperr.InvalidArgument("INVALID_INVITATION", "invitation is invalid, expired or already used")
+	ErrInvitationNotFound = apperr.NotFound("INVITATION_NOT_FOUND", "invitation not found")
+	// ErrWorkspaceForbidden — действие доступно только owner и admin пространства.
+	ErrWorkspaceForbidden = apperr.PermissionDenied("WORKSPACE_ROLE_REQUIRED", "workspace owner or admin role required")
+)
+
+// Invitation — приглашение в пространство по одноразовому токену.
+// Хранится только хеш токена.
+type Invitation struct {
+	ID          int64
+	WorkspaceID int64
+	Email       string
+	Role        WorkspaceRole
+	TokenHash   string
+	InvitedBy   int64
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+}
+
+type InvitationRepository interface {
+	Create(ctx context.Context, inv *Invitation) error
+	GetByID(ctx context.Context, id int64) (*Invitation, error)
+	// Accept принимает приглашение за userID, если оно выписано на его текущий
+	// email, добавляет его в пространство и подтверждает email: приглашение
+	// пришло на этот адрес. Повторно то же приглашение принять нельзя.
+	Accept(ctx context.Context, tokenHash string, userID int64) (*Invitation, error)
+	// AcceptAsNewUser создаёт пользователя u и принимает за него приглашение
+	// в одной транзакции: если приглашение уже не действует, аккаунт не создаётся.
+	AcceptAsNewUser(ctx context.Context, tokenHash string, u *User) (*Invitation, error)
+	Revoke(ctx context.Context, id int64) error
+	ListPending(ctx context.Context, workspaceID int64) ([]*Invitation, error)
+}
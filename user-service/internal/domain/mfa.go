@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+)
+
+var (
+	ErrMFANotEnrolled    = apperr.Conflict("MFA_NOT_ENROLLED", "two-factor authentication is not enrolled")
+	ErrMFAAlreadyEnabled = apperr.Conflict("MFA_ALREADY_ENABLED", "two-factor authentication is already enabled")
+	ErrInvalidCode       = apperr.InvalidField("code", "INVALID_CODE", "invalid verification code")
+)
+
+// MFA — состояние TOTP у пользователя. Secret задан с начала подключения,
+// EnabledAt — после подтверждения первым кодом.
+type MFA struct {
+	Secret    string
+	EnabledAt *time.Time
+	// LastStep — последний принятый шаг TOTP, его код повторно не принимается.
+	LastStep int64
+}
+
+// LoginResult — итог первого шага входа: либо токены, либо,
+// если включена 2FA, ChallengeToken для VerifySecondFactor.
+type LoginResult struct {
+	Tokens         *TokenPair
+	ChallengeToken string
+}
+
+// TOTPEnrollment — данные для подключения приложения-аутентификатора.
+type TOTPEnrollment struct {
+	Secret string
+	URI    string
+}
+
+type MFARepository interface {
+	Get(ctx context.Context, userID int64) (*MFA, error)
+	// SetPendingSecret сохраняет секрет, пока 2FA не включена.
+	SetPendingSecret(ctx context.Context, userID int64, secret string) error
+	// Enable включает 2FA и заменяет коды восстановления.
+	Enable(ctx context.Context, userID int64, recoveryHashes []string) error
+	Disable(ctx context.Context, userID int64) error
+	// UseStep запоминает использованный шаг TOTP. Возвращает false,
+	// если этот или более поздний шаг уже использовали — код повторно не принимается.
+	UseStep(ctx context.Context, userID, step int64) (bool, error)
+	// UseRecoveryCode гасит код восстановления, false если его нет или он использован.
+	UseRecoveryCode(ctx context.Context, userID int64, hash string) (bool, error)
+
+	CreateChallenge(ctx context.Context, userID int64, tokenHash string, expiresAt time.Time) error
+	// ChallengeUser возвращает владельца действующего вызова или ErrInvalidToken.
+	// Вызов перестаёт действовать после maxAttempts неудачных попыток.
+	ChallengeUser(ctx context.Context, tokenHash string, maxAttempts int) (int64, error)
+	FailChallenge(ctx context.Context, tokenHash string) error
+	// CompleteChallenge гасит вызов, false если его уже использовали.
+	CompleteChallenge(ctx context.Context, tokenHash string) (bool, error)
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+)
+
+// MinPasswordLength — минимальная длина нового пароля.
+const MinPasswordLength = 8
+
+var ErrWeakPassword = apperr.InvalidField("password", "WEAK_PASSWORD", "password must be at least 8 characters")
+
+type PasswordResetRepository interface {
+	// Create запоминает токен вместе с адресом, на который отправлена ссылка.
+	Create(ctx context.Context, userID int64, email, tokenHash string, expiresAt time.Time) error
+	// Consume использует действующий токен сброса и возвращает владельца
+	// и адрес, на который была отправлена ссылка.
+	// Остальные токены сброса этого пользователя тоже гасятся.
+	Consume(ctx context.Context, tokenHash string) (userID int64, email string, err error)
+}
+
+type PasswordUseCase interface {
+	// RequestReset отправляет письмо со ссылкой сброса, если email зарегистрирован.
+	// Ответ не зависит от того, есть ли такой пользователь.
+	RequestReset(ctx context.Context, email string) error
+	// Reset меняет пароль по токену из письма и завершает все сессии пользователя.
+	Reset(ctx context.Context, token, newPassword string) error
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+)
+
+var (
+	ErrPersonalTokenNotFound = apperr.NotFound("PERSONAL_TOKEN_NOT_FOUND", "personal access token not found")
+	// ErrInvalidScope — scope не существует или не входит в права пользователя.
+	ErrInvalidScope  = apperr.InvalidField("scopes", "INVALID_SCOPE", "invalid token scope")
+	ErrInvalidExpiry = apperr.InvalidField("expires_at", "INVALID_EXPIRY", "token expiry must be in the future")
+)
+
+// PersonalToken — долгоживущий токен для скриптов и CI. Права токена —
+// его Scopes, но не больше, чем у роли владельца на момент запроса.
+// Хранится только хеш токена.
+type PersonalToken struct {
+	ID         int64
+	UserID     int64
+	Name       string
+	Scopes     []string
+	TokenHash  string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+type PersonalTokenRepository interface {
+	Create(ctx context.Context, t *PersonalToken) error
+	// ListForUser возвращает неотозванные токены, в том числе истёкшие.
+	ListForUser(ctx context.Context, userID int64) ([]*PersonalToken, error)
+	// GetActive возвращает неотозванный и неистёкший токен или ErrInvalidToken.
+	GetActive(ctx context.Context, tokenHash string) (*PersonalToken, error)
+	// Touch обновляет время последнего использования.
+	Touch(ctx context.Context, id int64, at time.Time) error
+	// Revoke отзывает токен userID или возвращает ErrPersonalTokenNotFound.
+	Revoke(ctx context.Context, userID, id int64) error
+}
+
+type PersonalTokenUseCase interface {
+	// Create выпускает токен и возвращает его. Токен больше нигде не сохраняется.
+	Create(ctx context.Context, userID int64, name string, scopes []string, expiresAt *time.Time) (*PersonalToken, string, error)
+	List(ctx context.Context, userID int64) ([]*PersonalToken, error)
+	Revoke(ctx context.Context, userID, id int64) error
+	// Authenticate проверяет токен и возвращает его вместе с владельцем.
+	// Permissions владельца урезаны до scopes токена.
+	Authenticate(ctx context.Context, token string) (*PersonalToken, *User, error)
+}
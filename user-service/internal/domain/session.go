@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+)
+
+var ErrSessionNotFound = apperr.NotFound("SESSION_NOT_FOUND", "session not found")
+
+// ClientInfo — откуда пришёл запрос: адрес и User-Agent клиента.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// Session — один вход пользователя. Refresh токены сессии образуют семейство
+// с FamilyID = ID, а access token несут ID сессии в claim sid.
+type Session struct {
+	ID         string
+	UserID     int64
+	UserAgent  string
+	IP         string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+}
+
+type SessionRepository interface {
+	Create(ctx context.Context, s *Session) error
+	// Touch отмечает, что сессией пользовались с адреса ip.
+	Touch(ctx context.Context, id, ip string) error
+	// ListActive возвращает неотозванные сессии, которыми пользовались после since.
+	ListActive(ctx context.Context, userID int64, since time.Time) ([]*Session, error)
+	// Revoke отзывает сессию userID вместе с её refresh токенами
+	// или возвращает ErrSessionNotFound.
+	Revoke(ctx context.Context, userID int64, id string) error
+	// RevokeAllForUser отзывает все сессии пользователя и возвращает их ID.
+	RevokeAllForUser(ctx context.Context, userID int64) ([]string, error)
+}
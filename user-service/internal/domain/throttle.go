@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+)
+
+var ErrTooManyAttempts = apperr.ResourceExhausted("TOO_MANY_ATTEMPTS", "too many failed login attempts")
+
+// RateLimitError — попытка отклонена до проверки пароля, повторить можно через RetryAfter.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrTooManyAttempts, e.RetryAfter.Round(time.Second))
+}
+
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrTooManyAttempts
+}
+
+// As отдаёт вид и код ErrTooManyAttempts для apperr.ToStatus.
+func (e *RateLimitError) As(target any) bool {
+	if t, ok := target.(**apperr.Error); ok {
+		*t = ErrTooManyAttempts
+		return true
+	}
+	return false
+}
+
+// LoginThrottle ограничивает перебор паролей и кодов по аккаунту и по адресу клиента.
+type LoginThrottle interface {
+	// Reserve учитывает попытку до проверки пароля или кода и возвращает
+	// *RateLimitError, если её нужно отклонить. Проверка и учёт атомарны,
+	// поэтому параллельные запросы не обходят задержку. Неудачу отдельно
+	// сообщать не нужно: она уже учтена.
+	Reserve(ctx context.Context, email, ip string) error
+	// Succeed обнуляет счётчик аккаунта и возвращает адресу удачную попытку.
+	// Счётчик адреса не обнуляется, иначе вход в свой аккаунт позволял бы перебирать чужие.
+	Succeed(ctx context.Context, email, ip string) error
+}
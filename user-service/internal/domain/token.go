@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+)
+
+var ErrInvalidToken = apperr.Unauthenticated("INVALID_TOKEN", "invalid or expired token")
+
+// TokenPair — то, что получает клиент после входа или обновления токена.
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
+// RefreshToken — запись о выданном refresh token. Хранится только хеш.
+// Все токены, полученные ротацией из одного входа, образуют семейство FamilyID —
+// это ID сессии (Session). Отзываются токены вместе с сессией.
+type RefreshToken struct {
+	ID        int64
+	UserID    int64
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+}
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, t *RefreshToken) error
+	GetByHash(ctx context.Context, hash string) (*RefreshToken, error)
+	// MarkUsed помечает токен использованным. Возвращает false,
+	// если токен уже был использован или отозван — это повторное предъявление.
+	MarkUsed(ctx context.Context, id int64) (bool, error)
+}
+
+// TokenRevoker записывает ID отозванных access token.
+type TokenRevoker interface {
+	Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error
+}
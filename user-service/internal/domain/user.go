@@ -2,23 +2,123 @@ package domain
 
 import (
 	"context"
+	"strings"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/rbac"
+)
+
+var (
+	ErrUserNotFound    = apperr.NotFound("USER_NOT_FOUND", "user not found")
+	ErrInvalidEmail    = apperr.InvalidField("email", "INVALID_EMAIL", "invalid email")
+	ErrEmailTaken      = apperr.AlreadyExists("EMAIL_TAKEN", "email is already taken")
+	ErrInvalidPassword = apperr.PermissionDenied("WRONG_PASSWORD", "current password is incorrect")
+	ErrTooManyUsers    = apperr.InvalidField("ids", "TOO_MANY_USERS", "too many user ids requested")
+	// ErrInvalidCredentials не уточняет, что именно неверно: email или пароль.
+	ErrInvalidCredentials = apperr.Unauthenticated("INVALID_CREDENTIALS", "invalid credentials")
+	ErrNameRequired       = apperr.Required("name")
+	ErrInvalidRole        = apperr.InvalidField("role", "INVALID_ROLE", "invalid role")
 )
 
+// MaxUsersPerLookup — сколько пользователей можно запросить за раз в GetUsers.
+const MaxUsersPerLookup = 500
+
+// AccountDeletionGrace — сколько удалённый аккаунт хранится до окончательного
+// удаления. До этого вход с верным паролем восстанавливает аккаунт.
+const AccountDeletionGrace = 30 * 24 * time.Hour
+
+// NormalizeEmail приводит email к виду, в котором он хранится в users:
+// по нему ищут пользователя и считают попытки входа.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
 type User struct {
-	ID       int64  `json:"id"`
-	Name     string `json:"name" binding:"required"`
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
+	ID          int64     `json:"id"`
+	Name        string    `json:"name" binding:"required"`
+	Email       string    `json:"email" binding:"required,email"`
+	Password    string    `json:"password" binding:"required"`
+	Role        rbac.Role `json:"role"`
+	Permissions []string  `json:"permissions"`
+	// EmailVerifiedAt — когда пользователь подтвердил email, nil если ещё нет.
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+}
+
+// ProfileUpdate — частичное обновление профиля, nil означает "не менять".
+type ProfileUpdate struct {
+	Name  *string
+	Email *string
 }
 
 type UserRepository interface {
 	Create(ctx context.Context, user *User) error
+	// GetByEmail, GetByID и Profile не находят удалённых пользователей.
 	GetByEmail(ctx context.Context, email string) (*User, error)
+	// GetDeletedByEmail находит последнего пользователя с email, удалённого не раньше since.
+	GetDeletedByEmail(ctx context.Context, email string, since time.Time) (*User, error)
+	// GetByID, в отличие от Profile, загружает и хеш пароля.
+	GetByID(ctx context.Context, userID int64) (*User, error)
 	Profile(ctx context.Context, userID int) (*User, error)
+	// GetVisible возвращает пользователей из ids, которых видит viewerID:
+	// его самого и тех, с кем он состоит в общем пространстве.
+	GetVisible(ctx context.Context, viewerID int64, ids []int64) ([]*User, error)
+	// Permissions возвращает права роли из role_permissions.
+	Permissions(ctx context.Context, role rbac.Role) ([]string, error)
+	SetRole(ctx context.Context, userID int64, role rbac.Role) error
+	UpdatePassword(ctx context.Context, userID int64, hash string) error
+	// MarkEmailVerified подтверждает email, только если у пользователя всё ещё адрес email.
+	MarkEmailVerified(ctx context.Context, userID int64, email string) error
+	// Update сохраняет имя и email. Новый email снова требует подтверждения,
+	// а неиспользованные ссылки подтверждения и сброса пароля гасятся.
+	Update(ctx context.Context, user *User) error
+	SoftDelete(ctx context.Context, userID int64) error
+	// Restore отменяет SoftDelete. ErrEmailTaken — email уже занял новый аккаунт.
+	Restore(ctx context.Context, userID int64) error
+	// PurgeDeleted окончательно удаляет пользователей, удалённых раньше before.
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 }
 
 type UserUseCase interface {
 	Register(ctx context.Context, user *User) error
-	Login(ctx context.Context, email, password string) (string, error)
+	// Login проверяет пароль. Если у пользователя включена 2FA, вместо
+	// токенов возвращается вызов, который завершает VerifySecondFactor.
+	// client запоминается в сессии, его адрес нужен и для ограничения перебора.
+	// Вход в удалённый аккаунт в пределах AccountDeletionGrace отменяет удаление.
+	Login(ctx context.Context, email, password string, client ClientInfo) (*LoginResult, error)
 	Profile(ctx context.Context, userID int) (*User, error)
+	// GetUsers возвращает имена и email пользователей для отображения.
+	// Чужих пользователей, с которыми нет общего пространства, в ответе нет.
+	GetUsers(ctx context.Context, viewerID int64, ids []int64) ([]*User, error)
+	// Refresh обменивает refresh token на новую пару токенов.
+	Refresh(ctx context.Context, refreshToken string, client ClientInfo) (*TokenPair, error)
+	// Logout завершает сессию sessionID, отзывает access token tokenID и,
+	// если передан, refresh token.
+	Logout(ctx context.Context, userID int64, tokenID, sessionID string, expiresAt time.Time, refreshToken string) error
+	SetRole(ctx context.Context, userID int64, role rbac.Role) error
+
+	// VerifySecondFactor завершает вход кодом TOTP или кодом восстановления.
+	VerifySecondFactor(ctx context.Context, challengeToken, code string, client ClientInfo) (*TokenPair, error)
+	// EnrollTOTP начинает подключение 2FA: выдаёт секрет для приложения.
+	EnrollTOTP(ctx context.Context, userID int64) (*TOTPEnrollment, error)
+	// ConfirmTOTP включает 2FA первым кодом из приложения и возвращает коды восстановления.
+	// Неверные коды ограничиваются так же, как неверные пароли; ip — адрес клиента.
+	ConfirmTOTP(ctx context.Context, userID int64, code, ip string) ([]string, error)
+	DisableTOTP(ctx context.Context, userID int64, code, ip string) error
+
+	// UpdateProfile возвращает обновлённого пользователя и true, если сменился email.
+	UpdateProfile(ctx context.Context, userID int64, upd ProfileUpdate) (*User, bool, error)
+	// ChangePassword меняет пароль, завершает все сессии и выдаёт новые токены текущей.
+	ChangePassword(ctx context.Context, userID int64, current, next string, client ClientInfo) (*TokenPair, error)
+	// DeleteAccount помечает аккаунт удалённым и завершает все сессии, включая
+	// текущий access token tokenID. Данные удаляются через AccountDeletionGrace,
+	// до этого удаление отменяет вход (Login).
+	DeleteAccount(ctx context.Context, userID int64, password, ip, tokenID string, expiresAt time.Time) error
+	PurgeDeleted(ctx context.Context) (int64, error)
+
+	// ListSessions возвращает действующие сессии пользователя, свежие первыми.
+	ListSessions(ctx context.Context, userID int64) ([]*Session, error)
+	// RevokeSession завершает сессию: её refresh токены перестают работать,
+	// а access token отклоняются gateway'ем.
+	RevokeSession(ctx context.Context, userID int64, sessionID string) error
 }
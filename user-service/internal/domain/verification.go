@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+)
+
+var ErrEmailNotVerified = apperr.PermissionDenied("EMAIL_NOT_VERIFIED", "email is not verified")
+
+type EmailVerificationRepository interface {
+	// Create запоминает токен вместе с адресом, на который отправлена ссылка.
+	Create(ctx context.Context, userID int64, email, tokenHash string, expiresAt time.Time) error
+	// Consume использует действующий токен подтверждения и возвращает владельца.
+	// Токен недействителен, если с тех пор email пользователя сменился.
+	Consume(ctx context.Context, tokenHash string) (userID int64, email string, err error)
+}
+
+type VerificationUseCase interface {
+	// Send отправляет пользователю ссылку подтверждения email.
+	Send(ctx context.Context, user *User) error
+	Verify(ctx context.Context, token string) error
+	// Resend повторно отправляет ссылку, если email зарегистрирован и ещё не подтверждён.
+	// Ответ не зависит от того, есть ли такой пользователь.
+	Resend(ctx context.Context, email string) error
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+)
+
+var (
+	ErrWorkspaceNotFound = apperr.NotFound("WORKSPACE_NOT_FOUND", "workspace not found")
+	ErrNotMember         = apperr.PermissionDenied("NOT_WORKSPACE_MEMBER", "user is not a member of the workspace")
+	ErrSoleOwner         = apperr.Conflict("SOLE_WORKSPACE_OWNER", "user is the only owner of a workspace with other members")
+)
+
+type WorkspaceRole string
+
+const (
+	WorkspaceOwner  WorkspaceRole = "owner"
+	WorkspaceAdmin  WorkspaceRole = "admin"
+	WorkspaceMember WorkspaceRole = "member"
+)
+
+type Workspace struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name" binding:"required"`
+	CreatedBy int64  `json:"created_by"`
+	// Role — роль пользователя, для которого загружено пространство.
+	Role WorkspaceRole `json:"role"`
+}
+
+type Member struct {
+	UserID int64         `json:"user_id"`
+	Role   WorkspaceRole `json:"role"`
+}
+
+type WorkspaceRepository interface {
+	// Create создаёт пространство и делает создателя владельцем.
+	Create(ctx context.Context, w *Workspace) error
+	ListForUser(ctx context.Context, userID int64) ([]*Workspace, error)
+	// Membership возвращает роль участника или ErrNotMember.
+	Membership(ctx context.Context, workspaceID, userID int64) (WorkspaceRole, error)
+	Members(ctx context.Context, workspaceID int64) ([]*Member, error)
+	// SoleOwnerships возвращает пространства, где userID единственный владелец,
+	// а кроме него есть другие участники.
+	SoleOwnerships(ctx context.Context, userID int64) ([]*Workspace, error)
+}
+
+type WorkspaceUseCase interface {
+	Create(ctx context.Context, userID int64, name string) (*Workspace, error)
+	List(ctx context.Context, userID int64) ([]*Workspace, error)
+	// Membership проверяет, состоит ли userID в пространстве.
+	// Спрашивать может сам userID или другой участник пространства.
+	Membership(ctx context.Context, actorID, workspaceID, userID int64) (WorkspaceRole, error)
+	Members(ctx context.Context, actorID, workspaceID int64) ([]*Member, error)
+	// CheckCanLeave возвращает ErrSoleOwner, если после ухода пользователя
+	// в каком-то пространстве не останется владельца.
+	CheckCanLeave(ctx context.Context, userID int64) error
+
+	// Invite создаёт приглашение и возвращает его токен. Токен больше нигде не сохраняется.
+	Invite(ctx context.Context, actorID, workspaceID int64, email string, role WorkspaceRole) (*Invitation, string, error)
+	// RegisterInvited регистрирует нового пользователя по приглашению на его
+	// email. Уже зарегистрированный пользователь принимает приглашение через
+	// AcceptInvitation после входа.
+	RegisterInvited(ctx context.Context, u *User, token string) (*Invitation, error)
+	AcceptInvitation(ctx context.Context, userID int64, token string) (*Invitation, error)
+	RevokeInvitation(ctx context.Context, actorID, invitationID int64) error
+	ListInvitations(ctx context.Context, actorID, workspaceID int64) ([]*Invitation, error)
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/grpcauth"
+	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (h *UserHandler) UpdateProfile(ctx context.Context, req *authpb.UpdateProfileRequest) (*authpb.ProfileResponse, error) {
+	p, err := sessionPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		paths = []string{"name", "email"}
+	}
+
+	var upd domain.ProfileUpdate
+	for _, path := range paths {
+		switch path {
+		case "name":
+			if strings.TrimSpace(req.GetName()) == "" {
+				return nil, apperr.ToStatus(domain.ErrNameRequired)
+			}
+			name := req.GetName()
+			upd.Name = &name
+		case "email":
+			email := req.GetEmail()
+			upd.Email = &email
+		default:
+			return nil, apperr.ToStatus(apperr.InvalidField("update_mask", "UNKNOWN_FIELD", fmt.Sprintf("unknown field in update_mask: %q", path)))
+		}
+	}
+
+	user, emailChanged, err := h.uc.UpdateProfile(ctx, p.UserID, upd)
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	if emailChanged {
+		// профиль уже сохранён, ссылку можно запросить повторно
+		if err := h.vf.Send(ctx, user); err != nil {
+			log.Printf("не удалось отправить письмо подтверждения пользователю %d: %v", user.ID, err)
+		}
+	}
+	return toProfileResponse(user), nil
+}
+
+func (h *UserHandler) ChangePassword(ctx context.Context, req *authpb.ChangePasswordRequest) (*authpb.LoginResponse, error) {
+	p, err := sessionPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := h.uc.ChangePassword(ctx, p.UserID, req.GetCurrentPassword(), req.GetNewPassword(), clientInfo(ctx))
+	var rl *domain.RateLimitError
+	if errors.As(err, &rl) {
+		return nil, tooManyAttempts(ctx, rl)
+	}
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+	return toLoginResponse(tokens), nil
+}
+
+func (h *UserHandler) DeleteAccount(ctx context.Context, req *authpb.DeleteAccountRequest) (*authpb.DeleteAccountResponse, error) {
+	p, err := sessionPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// пространство не должно остаться без владельца
+	if err := h.ws.CheckCanLeave(ctx, p.UserID); err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	err = h.uc.DeleteAccount(ctx, p.UserID, req.GetPassword(), grpcauth.ClientIP(ctx), p.TokenID, time.Unix(p.ExpiresAt, 0))
+	var rl *domain.RateLimitError
+	if errors.As(err, &rl) {
+		return nil, tooManyAttempts(ctx, rl)
+	}
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &authpb.DeleteAccountResponse{
+		Message:    "Account has been deleted, log in before purge_after to restore it",
+		PurgeAfter: timestamppb.New(time.Now().Add(domain.AccountDeletionGrace)),
+	}, nil
+}
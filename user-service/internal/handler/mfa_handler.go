@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/grpcauth"
+	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+)
+
+func (h *UserHandler) VerifySecondFactor(ctx context.Context, req *authpb.VerifySecondFactorRequest) (*authpb.LoginResponse, error) {
+	if req.GetChallengeToken() == "" {
+		return nil, apperr.ToStatus(apperr.Required("challenge_token"))
+	}
+	if req.GetCode() == "" {
+		return nil, apperr.ToStatus(apperr.Required("code"))
+	}
+
+	tokens, err := h.uc.VerifySecondFactor(ctx, req.GetChallengeToken(), req.GetCode(), clientInfo(ctx))
+	var rl *domain.RateLimitError
+	if errors.As(err, &rl) {
+		return nil, tooManyAttempts(ctx, rl)
+	}
+	if errors.Is(err, domain.ErrInvalidCode) {
+		// при входе неверный код — это неудачная попытка входа
+		err = apperr.WithKind(err, apperr.KindUnauthenticated)
+	}
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+	return toLoginResponse(tokens), nil
+}
+
+func (h *UserHandler) EnrollTOTP(ctx context.Context, req *authpb.EnrollTOTPRequest) (*authpb.EnrollTOTPResponse, error) {
+	p, err := sessionPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment, err := h.uc.EnrollTOTP(ctx, p.UserID)
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &authpb.EnrollTOTPResponse{Secret: enrollment.Secret, OtpauthUri: enrollment.URI}, nil
+}
+
+func (h *UserHandler) ConfirmTOTP(ctx context.Context, req *authpb.ConfirmTOTPRequest) (*authpb.ConfirmTOTPResponse, error) {
+	p, err := sessionPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := h.uc.ConfirmTOTP(ctx, p.UserID, req.GetCode(), grpcauth.ClientIP(ctx))
+	var rl *domain.RateLimitError
+	if errors.As(err, &rl) {
+		return nil, tooManyAttempts(ctx, rl)
+	}
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &authpb.ConfirmTOTPResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (h *UserHandler) DisableTOTP(ctx context.Context, req *authpb.DisableTOTPRequest) (*authpb.DisableTOTPResponse, error) {
+	p, err := sessionPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.uc.DisableTOTP(ctx, p.UserID, req.GetCode(), grpcauth.ClientIP(ctx))
+	var rl *domain.RateLimitError
+	if errors.As(err, &rl) {
+		return nil, tooManyAttempts(ctx, rl)
+	}
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &authpb.DisableTOTPResponse{Message: "Two-factor authentication disabled"}, nil
+}
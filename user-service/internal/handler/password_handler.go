@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+)
+
+func (h *UserHandler) RequestPasswordReset(ctx context.Context, req *authpb.RequestPasswordResetRequest) (*authpb.RequestPasswordResetResponse, error) {
+	if req.GetEmail() == "" {
+		return nil, apperr.ToStatus(apperr.Required("email"))
+	}
+
+	if err := h.pw.RequestReset(ctx, req.GetEmail()); err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &authpb.RequestPasswordResetResponse{
+		Message: "If the email is registered, a reset link has been sent",
+	}, nil
+}
+
+func (h *UserHandler) ResetPassword(ctx context.Context, req *authpb.ResetPasswordRequest) (*authpb.ResetPasswordResponse, error) {
+	if req.GetToken() == "" {
+		return nil, apperr.ToStatus(apperr.Required("token"))
+	}
+
+	err := h.pw.Reset(ctx, req.GetToken(), req.GetNewPassword())
+	if errors.Is(err, domain.ErrInvalidToken) {
+		// токен из письма, а не вход: клиенту нужен 400, а не 401
+		err = apperr.WithKind(err, apperr.KindInvalidArgument)
+	}
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &authpb.ResetPasswordResponse{Message: "Password has been reset"}, nil
+}
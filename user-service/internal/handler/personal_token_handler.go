@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/grpcauth"
+	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// sessionPrincipal — как grpcauth.FromContext, но отклоняет вызовы по personal
+// access token: аккаунтом, токенами, сессиями и пространствами можно управлять
+// только из обычной сессии. Scopes токена касаются только задач и пользователей.
+func sessionPrincipal(ctx context.Context) (*grpcauth.Principal, error) {
+	p, ok := grpcauth.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+	if p.PersonalTokenID != 0 {
+		return nil, status.Error(codes.PermissionDenied, "not allowed with a personal access token")
+	}
+	return p, nil
+}
+
+func (h *UserHandler) CreatePersonalToken(ctx context.Context, req *authpb.CreatePersonalTokenRequest) (*authpb.CreatePersonalTokenResponse, error) {
+	p, err := sessionPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.GetName()) == "" {
+		return nil, apperr.ToStatus(domain.ErrNameRequired)
+	}
+
+	var expiresAt *time.Time
+	if req.GetExpiresAt() != nil {
+		t := req.GetExpiresAt().AsTime()
+		expiresAt = &t
+	}
+
+	t, token, err := h.pat.Create(ctx, p.UserID, req.GetName(), req.GetScopes(), expiresAt)
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &authpb.CreatePersonalTokenResponse{PersonalToken: toProtoPersonalToken(t), Token: token}, nil
+}
+
+func (h *UserHandler) ListPersonalTokens(ctx context.Context, req *authpb.ListPersonalTokensRequest) (*authpb.ListPersonalTokensResponse, error) {
+	p, err := sessionPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := h.pat.List(ctx, p.UserID)
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	resp := &authpb.ListPersonalTokensResponse{}
+	for _, t := range tokens {
+		resp.Tokens = append(resp.Tokens, toProtoPersonalToken(t))
+	}
+	return resp, nil
+}
+
+func (h *UserHandler) RevokePersonalToken(ctx context.Context, req *authpb.RevokePersonalTokenRequest) (*authpb.RevokePersonalTokenResponse, error) {
+	p, err := sessionPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.pat.Revoke(ctx, p.UserID, req.GetId()); err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+	return &authpb.RevokePersonalTokenResponse{Message: "Token revoked"}, nil
+}
+
+func (h *UserHandler) AuthenticatePersonalToken(ctx context.Context, req *authpb.AuthenticatePersonalTokenRequest) (*authpb.AuthenticatePersonalTokenResponse, error) {
+	t, u, err := h.pat.Authenticate(ctx, req.GetToken())
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &authpb.AuthenticatePersonalTokenResponse{
+		UserId:      u.ID,
+		Email:       u.Email,
+		Role:        string(u.Role),
+		Permissions: u.Permissions,
+		TokenId:     t.ID,
+	}, nil
+}
+
+func toProtoPersonalToken(t *domain.PersonalToken) *authpb.PersonalToken {
+	pt := &authpb.PersonalToken{
+		Id:        t.ID,
+		Name:      t.Name,
+		Scopes:    t.Scopes,
+		CreatedAt: timestamppb.New(t.CreatedAt),
+	}
+	if t.ExpiresAt != nil {
+		pt.ExpiresAt = timestamppb.New(*t.ExpiresAt)
+	}
+	if t.LastUsedAt != nil {
+		pt.LastUsedAt = timestamppb.New(*t.LastUsedAt)
+	}
+	return pt
+}
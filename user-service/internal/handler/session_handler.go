@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/grpcauth"
+	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// clientInfo — адрес и User-Agent клиента, переданные gateway'ем.
+func clientInfo(ctx context.Context) domain.ClientInfo {
+	return domain.ClientInfo{IP: grpcauth.ClientIP(ctx), UserAgent: grpcauth.UserAgent(ctx)}
+}
+
+func (h *UserHandler) ListSessions(ctx context.Context, req *authpb.ListSessionsRequest) (*authpb.ListSessionsResponse, error) {
+	p, err := sessionPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := h.uc.ListSessions(ctx, p.UserID)
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	resp := &authpb.ListSessionsResponse{}
+	for _, s := range sessions {
+		resp.Sessions = append(resp.Sessions, &authpb.Session{
+			Id:         s.ID,
+			UserAgent:  s.UserAgent,
+			Ip:         s.IP,
+			CreatedAt:  timestamppb.New(s.CreatedAt),
+			LastSeenAt: timestamppb.New(s.LastSeenAt),
+			Current:    s.ID == p.SessionID,
+		})
+	}
+	return resp, nil
+}
+
+func (h *UserHandler) RevokeSession(ctx context.Context, req *authpb.RevokeSessionRequest) (*authpb.RevokeSessionResponse, error) {
+	p, err := sessionPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetId() == "" {
+		return nil, apperr.ToStatus(apperr.Required("id"))
+	}
+
+	err = h.uc.RevokeSession(ctx, p.UserID, req.GetId())
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &authpb.RevokeSessionResponse{Message: "Session revoked"}, nil
+}
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// retryAfterKey — trailer с числом секунд до следующей попытки.
+const retryAfterKey = "retry-after"
+
+// tooManyAttempts возвращает ResourceExhausted и сообщает клиенту, когда можно повторить.
+func tooManyAttempts(ctx context.Context, rl *domain.RateLimitError) error {
+	seconds := int64(math.Ceil(rl.RetryAfter.Seconds()))
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(retryAfterKey, strconv.FormatInt(seconds, 10)))
+	return apperr.ToStatus(rl)
+}
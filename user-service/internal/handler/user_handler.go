@@ -3,27 +3,103 @@ package handler
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"time"
 
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/grpcauth"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/jwks"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/rbac"
 	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
 	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type UserHandler struct {
 	authpb.UnimplementedAuthServiceServer
-	uc domain.UserUseCase
+	uc   domain.UserUseCase
+	ws   domain.WorkspaceUseCase
+	pw   domain.PasswordUseCase
+	vf   domain.VerificationUseCase
+	pat  domain.PersonalTokenUseCase
+	keys *jwks.KeySet
 }
 
-func NewUserHandler(uc domain.UserUseCase) *UserHandler {
-	return &UserHandler{uc: uc}
+func NewUserHandler(uc domain.UserUseCase, ws domain.WorkspaceUseCase, pw domain.PasswordUseCase, vf domain.VerificationUseCase, pat domain.PersonalTokenUseCase, keys *jwks.KeySet) *UserHandler {
+	return &UserHandler{uc: uc, ws: ws, pw: pw, vf: vf, pat: pat, keys: keys}
 }
 
 func (h *UserHandler) Login(ctx context.Context, req *authpb.LoginRequest) (*authpb.LoginResponse, error) {
-	token, err := h.uc.Login(ctx, req.GetEmail(), req.GetPassword())
+	res, err := h.uc.Login(ctx, req.GetEmail(), req.GetPassword(), clientInfo(ctx))
+	var rl *domain.RateLimitError
+	if errors.As(err, &rl) {
+		return nil, tooManyAttempts(ctx, rl)
+	}
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	if res.ChallengeToken != "" {
+		return &authpb.LoginResponse{MfaRequired: true, ChallengeToken: res.ChallengeToken}, nil
+	}
+	return toLoginResponse(res.Tokens), nil
+}
+
+func toLoginResponse(tokens *domain.TokenPair) *authpb.LoginResponse {
+	return &authpb.LoginResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    int64(tokens.ExpiresIn.Seconds()),
+	}
+}
+
+func (h *UserHandler) Refresh(ctx context.Context, req *authpb.RefreshRequest) (*authpb.RefreshResponse, error) {
+	tokens, err := h.uc.Refresh(ctx, req.GetRefreshToken(), clientInfo(ctx))
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &authpb.RefreshResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    int64(tokens.ExpiresIn.Seconds()),
+	}, nil
+}
+
+func (h *UserHandler) Logout(ctx context.Context, req *authpb.LogoutRequest) (*authpb.LogoutResponse, error) {
+	// personal access token отзывают через RevokePersonalToken
+	p, err := sessionPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.uc.Logout(ctx, p.UserID, p.TokenID, p.SessionID, time.Unix(p.ExpiresAt, 0), req.GetRefreshToken())
+	if errors.Is(err, domain.ErrInvalidToken) {
+		return nil, status.Error(codes.PermissionDenied, "refresh token belongs to another user")
+	}
 	if err != nil {
-		return nil, errors.New(err.Error())
+		return nil, apperr.ToStatus(err)
 	}
 
-	return &authpb.LoginResponse{Token: token}, nil
+	return &authpb.LogoutResponse{Message: "Logged out successfully"}, nil
+}
+
+// JWKS отдаёт публичные ключи, которыми можно проверить выданные токены.
+func (h *UserHandler) JWKS(ctx context.Context, req *authpb.JWKSRequest) (*authpb.JWKSResponse, error) {
+	resp := &authpb.JWKSResponse{}
+	for _, k := range h.keys.JWKs() {
+		resp.Keys = append(resp.Keys, &authpb.JWK{
+			Kty: k.Kty,
+			Kid: k.Kid,
+			Use: k.Use,
+			Alg: k.Alg,
+			Crv: k.Crv,
+			X:   k.X,
+		})
+	}
+	return resp, nil
 }
 
 func (h *UserHandler) Register(c context.Context, req *authpb.RegisterRequest) (*authpb.RegisterResponse, error) {
@@ -34,8 +110,24 @@ func (h *UserHandler) Register(c context.Context, req *authpb.RegisterRequest) (
 		Password: req.GetPassword(),
 	}
 
+	// по приглашению email уже подтверждён: оно пришло на этот адрес
+	if token := req.GetInviteToken(); token != "" {
+		if _, err := h.ws.RegisterInvited(c, &user, token); err != nil {
+			return nil, apperr.ToStatus(err)
+		}
+		return &authpb.RegisterResponse{
+			Id:      user.ID,
+			Message: "User Created Successfully",
+		}, nil
+	}
+
 	if err := h.uc.Register(c, &user); err != nil {
-		return nil, errors.New(err.Error())
+		return nil, apperr.ToStatus(err)
+	}
+
+	// письмо не дошло — не повод отменять регистрацию, ссылку можно запросить ещё раз
+	if err := h.vf.Send(c, &user); err != nil {
+		log.Printf("не удалось отправить письмо подтверждения пользователю %d: %v", user.ID, err)
 	}
 	return &authpb.RegisterResponse{
 		Id:      user.ID,
@@ -44,16 +136,70 @@ func (h *UserHandler) Register(c context.Context, req *authpb.RegisterRequest) (
 }
 
 func (h *UserHandler) Profile(c context.Context, req *authpb.ProfileRequest) (*authpb.ProfileResponse, error) {
+	p, err := sessionPrincipal(c)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetId() != p.UserID {
+		return nil, status.Error(codes.PermissionDenied, "can not read another user's profile")
+	}
+
 	user, err := h.uc.Profile(c, int(req.GetId()))
 
 	if err != nil {
-		return nil, errors.New(err.Error())
+		return nil, apperr.ToStatus(err)
 	}
 
+	return toProfileResponse(user), nil
+
+}
+
+func toProfileResponse(user *domain.User) *authpb.ProfileResponse {
 	return &authpb.ProfileResponse{
-		Id:    user.ID,
-		Name:  user.Name,
-		Email: user.Email,
-	}, nil
+		Id:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		Role:          string(user.Role),
+		Permissions:   user.Permissions,
+		EmailVerified: user.EmailVerifiedAt != nil,
+	}
+}
+
+func (h *UserHandler) GetUsers(ctx context.Context, req *authpb.GetUsersRequest) (*authpb.GetUsersResponse, error) {
+	p, ok := grpcauth.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	users, err := h.uc.GetUsers(ctx, p.UserID, req.GetIds())
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	resp := &authpb.GetUsersResponse{}
+	for _, u := range users {
+		resp.Users = append(resp.Users, &authpb.User{Id: u.ID, Name: u.Name, Email: u.Email})
+	}
+	return resp, nil
+}
+
+func (h *UserHandler) SetUserRole(ctx context.Context, req *authpb.SetUserRoleRequest) (*authpb.SetUserRoleResponse, error) {
+	p, ok := grpcauth.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+	if !p.Can(rbac.UsersManage) {
+		return nil, status.Error(codes.PermissionDenied, "permission users:manage required")
+	}
+
+	role := rbac.Role(req.GetRole())
+	if !role.Valid() {
+		return nil, apperr.ToStatus(fmt.Errorf("%w: %q", domain.ErrInvalidRole, req.GetRole()))
+	}
+
+	if err := h.uc.SetRole(ctx, req.GetUserId(), role); err != nil {
+		return nil, apperr.ToStatus(err)
+	}
 
+	return &authpb.SetUserRoleResponse{Message: "Role updated successfully"}, nil
 }
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+)
+
+func (h *UserHandler) VerifyEmail(ctx context.Context, req *authpb.VerifyEmailRequest) (*authpb.VerifyEmailResponse, error) {
+	if req.GetToken() == "" {
+		return nil, apperr.ToStatus(apperr.Required("token"))
+	}
+
+	err := h.vf.Verify(ctx, req.GetToken())
+	if errors.Is(err, domain.ErrInvalidToken) {
+		// токен из письма, а не вход: клиенту нужен 400, а не 401
+		err = apperr.WithKind(err, apperr.KindInvalidArgument)
+	}
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &authpb.VerifyEmailResponse{Message: "Email verified successfully"}, nil
+}
+
+func (h *UserHandler) ResendVerification(ctx context.Context, req *authpb.ResendVerificationRequest) (*authpb.ResendVerificationResponse, error) {
+	if req.GetEmail() == "" {
+		return nil, apperr.ToStatus(apperr.Required("email"))
+	}
+
+	if err := h.vf.Resend(ctx, req.GetEmail()); err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &authpb.ResendVerificationResponse{
+		Message: "If the email is registered and not verified, a new link has been sent",
+	}, nil
+}
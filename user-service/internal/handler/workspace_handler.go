@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/apperr"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/grpcauth"
+	authpb "github.com/Murodkadirkhanoff/taqsym.uz/proto/auth"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (h *UserHandler) CreateWorkspace(ctx context.Context, req *authpb.CreateWorkspaceRequest) (*authpb.CreateWorkspaceResponse, error) {
+	p, err := sessionPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetName() == "" {
+		return nil, apperr.ToStatus(domain.ErrNameRequired)
+	}
+
+	w, err := h.ws.Create(ctx, p.UserID, req.GetName())
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &authpb.CreateWorkspaceResponse{Workspace: toProtoWorkspace(w)}, nil
+}
+
+func (h *UserHandler) ListWorkspaces(ctx context.Context, req *authpb.ListWorkspacesRequest) (*authpb.ListWorkspacesResponse, error) {
+	p, ok := grpcauth.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	workspaces, err := h.ws.List(ctx, p.UserID)
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	resp := &authpb.ListWorkspacesResponse{}
+	for _, w := range workspaces {
+		resp.Workspaces = append(resp.Workspaces, toProtoWorkspace(w))
+	}
+	return resp, nil
+}
+
+func (h *UserHandler) GetMembership(ctx context.Context, req *authpb.GetMembershipRequest) (*authpb.GetMembershipResponse, error) {
+	p, ok := grpcauth.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	userID := req.GetUserId()
+	if userID == 0 {
+		userID = p.UserID
+	}
+
+	role, err := h.ws.Membership(ctx, p.UserID, req.GetWorkspaceId(), userID)
+	if errors.Is(err, domain.ErrNotMember) {
+		if userID != p.UserID {
+			return nil, apperr.ToStatus(err)
+		}
+		return &authpb.GetMembershipResponse{Member: false}, nil
+	}
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &authpb.GetMembershipResponse{Member: true, Role: string(role)}, nil
+}
+
+func (h *UserHandler) ListWorkspaceMembers(ctx context.Context, req *authpb.ListWorkspaceMembersRequest) (*authpb.ListWorkspaceMembersResponse, error) {
+	p, ok := grpcauth.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	members, err := h.ws.Members(ctx, p.UserID, req.GetWorkspaceId())
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	resp := &authpb.ListWorkspaceMembersResponse{}
+	for _, m := range members {
+		resp.Members = append(resp.Members, &authpb.WorkspaceMember{UserId: m.UserID, Role: string(m.Role)})
+	}
+	return resp, nil
+}
+
+func toProtoWorkspace(w *domain.Workspace) *authpb.Workspace {
+	return &authpb.Workspace{
+		Id:   w.ID,
+		Name: w.Name,
+		Role: string(w.Role),
+	}
+}
+
+func (h *UserHandler) InviteMember(ctx context.Context, req *authpb.InviteMemberRequest) (*authpb.InviteMemberResponse, error) {
+	p, err := sessionPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	role := domain.WorkspaceRole(req.GetRole())
+	if role == "" {
+		role = domain.WorkspaceMember
+	}
+	if role != domain.WorkspaceMember && role != domain.WorkspaceAdmin {
+		return nil, apperr.ToStatus(fmt.Errorf("%w: can not invite with role %q", domain.ErrInvalidRole, req.GetRole()))
+	}
+
+	inv, token, err := h.ws.Invite(ctx, p.UserID, req.GetWorkspaceId(), req.GetEmail(), role)
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &authpb.InviteMemberResponse{Invitation: toProtoInvitation(inv), Token: token}, nil
+}
+
+func (h *UserHandler) AcceptInvitation(ctx context.Context, req *authpb.AcceptInvitationRequest) (*authpb.AcceptInvitationResponse, error) {
+	p, err := sessionPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetToken() == "" {
+		return nil, apperr.ToStatus(apperr.Required("token"))
+	}
+
+	inv, err := h.ws.AcceptInvitation(ctx, p.UserID, req.GetToken())
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &authpb.AcceptInvitationResponse{WorkspaceId: inv.WorkspaceID, Role: string(inv.Role)}, nil
+}
+
+func (h *UserHandler) RevokeInvitation(ctx context.Context, req *authpb.RevokeInvitationRequest) (*authpb.RevokeInvitationResponse, error) {
+	p, err := sessionPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.ws.RevokeInvitation(ctx, p.UserID, req.GetId()); err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	return &authpb.RevokeInvitationResponse{Message: "Invitation revoked successfully"}, nil
+}
+
+func (h *UserHandler) ListInvitations(ctx context.Context, req *authpb.ListInvitationsRequest) (*authpb.ListInvitationsResponse, error) {
+	p, err := sessionPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := h.ws.ListInvitations(ctx, p.UserID, req.GetWorkspaceId())
+	if err != nil {
+		return nil, apperr.ToStatus(err)
+	}
+
+	resp := &authpb.ListInvitationsResponse{}
+	for _, inv := range invitations {
+		resp.Invitations = append(resp.Invitations, toProtoInvitation(inv))
+	}
+	return resp, nil
+}
+
+func toProtoInvitation(inv *domain.Invitation) *authpb.Invitation {
+	return &authpb.Invitation{
+		Id:          inv.ID,
+		WorkspaceId: inv.WorkspaceID,
+		Email:       inv.Email,
+		Role:        string(inv.Role),
+		InvitedBy:   inv.InvitedBy,
+		ExpiresAt:   timestamppb.New(inv.ExpiresAt),
+		CreatedAt:   timestamppb.New(inv.CreatedAt),
+	}
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+)
+
+type emailVerificationRepo struct {
+	db *sql.DB
+}
+
+func NewEmailVerificationRepo(db *sql.DB) domain.EmailVerificationRepository {
+	return &emailVerificationRepo{db: db}
+}
+
+func (r *emailVerificationRepo) Create(ctx context.Context, userID int64, email, tokenHash string, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		"INSERT INTO email_verifications (user_id, email, token_hash, expires_at) VALUES ($1, $2, $3, $4)",
+		userID, email, tokenHash, expiresAt)
+	return err
+}
+
+func (r *emailVerificationRepo) Consume(ctx context.Context, tokenHash string) (int64, string, error) {
+	var (
+		userID int64
+		email  string
+	)
+	err := r.db.QueryRowContext(ctx,
+		`UPDATE email_verifications v SET used_at = now()
+		FROM users u
+		WHERE v.token_hash = $1 AND v.used_at IS NULL AND v.expires_at > now()
+			AND u.id = v.user_id AND u.email = v.email
+		RETURNING v.user_id, v.email`, tokenHash).Scan(&userID, &email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, "", domain.ErrInvalidToken
+	}
+	return userID, email, err
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+)
+
+type invitationRepo struct {
+	db *sql.DB
+}
+
+func NewInvitationRepo(db *sql.DB) domain.InvitationRepository {
+	return &invitationRepo{db: db}
+}
+
+const invitationColumns = "id, workspace_id, email, role, token_hash, invited_by, expires_at, created_at"
+
+// pendingInvitation — приглашение ещё можно принять.
+const pendingInvitation = "accepted_at IS NULL AND revoked_at IS NULL AND expires_at > now()"
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanInvitation(row scanner) (*domain.Invitation, error) {
+	var inv domain.Invitation
+	err := row.Scan(&inv.ID, &inv.WorkspaceID, &inv.Email, &inv.Role, &inv.TokenHash,
+		&inv.InvitedBy, &inv.ExpiresAt, &inv.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+func (r *invitationRepo) Create(ctx context.Context, inv *domain.Invitation) error {
+	return r.db.QueryRowContext(ctx,
+		`INSERT INTO workspace_invitations (workspace_id, email, role, token_hash, invited_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`,
+		inv.WorkspaceID, inv.Email, inv.Role, inv.TokenHash, inv.InvitedBy, inv.ExpiresAt).Scan(&inv.ID, &inv.CreatedAt)
+}
+
+func (r *invitationRepo) GetByID(ctx context.Context, id int64) (*domain.Invitation, error) {
+	inv, err := scanInvitation(r.db.QueryRowContext(ctx,
+		"SELECT "+invitationColumns+" FROM workspace_invitations WHERE id = $1", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrInvitationNotFound
+	}
+	return inv, err
+}
+
+func (r *invitationRepo) Accept(ctx context.Context, tokenHash string, userID int64) (*domain.Invitation, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	inv, err := acceptInvitation(ctx, tx, tokenHash, userID)
+	if err != nil {
+		return nil, err
+	}
+	return inv, tx.Commit()
+}
+
+func (r *invitationRepo) AcceptAsNewUser(ctx context.Context, tokenHash string, u *domain.User) (*domain.Invitation, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := insertUser(ctx, tx, u); err != nil {
+		return nil, err
+	}
+	inv, err := acceptInvitation(ctx, tx, tokenHash, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.QueryRowContext(ctx,
+		"SELECT email_verified_at FROM users WHERE id = $1", u.ID).Scan(&u.EmailVerifiedAt); err != nil {
+		return nil, err
+	}
+	return inv, tx.Commit()
+}
+
+// acceptInvitation принимает приглашение за userID, если оно выписано на его
+// email, подтверждает этот email и добавляет пользователя в пространство.
+func acceptInvitation(ctx context.Context, tx *sql.Tx, tokenHash string, userID int64) (*domain.Invitation, error) {
+	// условие в UPDATE делает приглашение одноразовым даже при гонке
+	inv, err := scanInvitation(tx.QueryRowContext(ctx,
+		`UPDATE workspace_invitations SET accepted_at = now(), accepted_by = $1
+		WHERE token_hash = $2 AND `+pendingInvitation+`
+			AND lower(email) = (SELECT email FROM users WHERE id = $1 AND deleted_at IS NULL)
+		RETURNING `+invitationColumns,
+		userID, tokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrInvalidInvitation
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE users SET email_verified_at = now() WHERE id = $1 AND email_verified_at IS NULL", userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// уже состоящий в пространстве пользователь сохраняет свою роль
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING",
+		inv.WorkspaceID, userID, inv.Role)
+	if err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+func (r *invitationRepo) Revoke(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx,
+		"UPDATE workspace_invitations SET revoked_at = now() WHERE id = $1 AND "+pendingInvitation, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrInvitationNotFound
+	}
+	return nil
+}
+
+func (r *invitationRepo) ListPending(ctx context.Context, workspaceID int64) ([]*domain.Invitation, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT "+invitationColumns+" FROM workspace_invitations WHERE workspace_id = $1 AND "+pendingInvitation+" ORDER BY id",
+		workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invitations []*domain.Invitation
+	for rows.Next() {
+		inv, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, inv)
+	}
+	return invitations, rows.Err()
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/pkg/secretbox"
+)
+
+// mfaRepo хранит секрет TOTP зашифрованным box и привязанным к пользователю.
+type mfaRepo struct {
+	db  *sql.DB
+	box *secretbox.Box
+}
+
+func NewMFARepo(db *sql.DB, box *secretbox.Box) domain.MFARepository {
+	return &mfaRepo{db: db, box: box}
+}
+
+func (r *mfaRepo) Get(ctx context.Context, userID int64) (*domain.MFA, error) {
+	var (
+		m      domain.MFA
+		secret sql.NullString
+	)
+	err := r.db.QueryRowContext(ctx,
+		"SELECT totp_secret, totp_enabled_at, totp_last_step FROM users WHERE id = $1", userID).Scan(&secret, &m.EnabledAt, &m.LastStep)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !secret.Valid || secret.String == "" {
+		return &m, nil
+	}
+
+	if !secretbox.IsSealed(secret.String) {
+		// секрет записан до шифрования: шифруем при первом чтении
+		if err := r.sealLegacy(ctx, userID, secret.String); err != nil {
+			return nil, err
+		}
+		m.Secret = secret.String
+		return &m, nil
+	}
+	m.Secret, err = r.box.Open(secret.String, secretContext(userID))
+	if err != nil {
+		return nil, fmt.Errorf("totp secret of user %d: %w", userID, err)
+	}
+	return &m, nil
+}
+
+func (r *mfaRepo) sealLegacy(ctx context.Context, userID int64, secret string) error {
+	sealed, err := r.box.Seal(secret, secretContext(userID))
+	if err != nil {
+		return err
+	}
+	// условие на старое значение не даёт затереть секрет, сменившийся за это время
+	_, err = r.db.ExecContext(ctx,
+		"UPDATE users SET totp_secret = $1 WHERE id = $2 AND totp_secret = $3", sealed, userID, secret)
+	return err
+}
+
+// secretContext привязывает зашифрованный секрет к пользователю.
+func secretContext(userID int64) string {
+	return "totp:" + strconv.FormatInt(userID, 10)
+}
+
+func (r *mfaRepo) SetPendingSecret(ctx context.Context, userID int64, secret string) error {
+	sealed, err := r.box.Seal(secret, secretContext(userID))
+	if err != nil {
+		return err
+	}
+	res, err := r.db.ExecContext(ctx,
+		"UPDATE users SET totp_secret = $1, totp_last_step = 0 WHERE id = $2 AND totp_enabled_at IS NULL",
+		sealed, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrMFAAlreadyEnabled
+	}
+	return nil
+}
+
+func (r *mfaRepo) Enable(ctx context.Context, userID int64, recoveryHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		"UPDATE users SET totp_enabled_at = now() WHERE id = $1 AND totp_secret IS NOT NULL AND totp_enabled_at IS NULL",
+		userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrMFAAlreadyEnabled
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM recovery_codes WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+	for _, hash := range recoveryHashes {
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)", userID, hash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *mfaRepo) Disable(ctx context.Context, userID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = 0 WHERE id = $1", userID)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM recovery_codes WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *mfaRepo) UseStep(ctx context.Context, userID, step int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		"UPDATE users SET totp_last_step = $1 WHERE id = $2 AND totp_last_step < $1", step, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (r *mfaRepo) UseRecoveryCode(ctx context.Context, userID int64, hash string) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		"UPDATE recovery_codes SET used_at = now() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL",
+		userID, hash)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (r *mfaRepo) CreateChallenge(ctx context.Context, userID int64, tokenHash string, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		"INSERT INTO login_challenges (user_id, token_hash, expires_at) VALUES ($1, $2, $3)",
+		userID, tokenHash, expiresAt)
+	return err
+}
+
+func (r *mfaRepo) ChallengeUser(ctx context.Context, tokenHash string, maxAttempts int) (int64, error) {
+	var userID int64
+	err := r.db.QueryRowContext(ctx,
+		`SELECT user_id FROM login_challenges
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > now() AND attempts < $2`,
+		tokenHash, maxAttempts).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, domain.ErrInvalidToken
+	}
+	return userID, err
+}
+
+func (r *mfaRepo) FailChallenge(ctx context.Context, tokenHash string) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE login_challenges SET attempts = attempts + 1 WHERE token_hash = $1", tokenHash)
+	return err
+}
+
+func (r *mfaRepo) CompleteChallenge(ctx context.Context, tokenHash string) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		"UPDATE login_challenges SET used_at = now() WHERE token_hash = $1 AND used_at IS NULL", tokenHash)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
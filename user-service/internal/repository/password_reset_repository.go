@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+)
+
+type passwordResetRepo struct {
+	db *sql.DB
+}
+
+func NewPasswordResetRepo(db *sql.DB) domain.PasswordResetRepository {
+	return &passwordResetRepo{db: db}
+}
+
+func (r *passwordResetRepo) Create(ctx context.Context, userID int64, email, tokenHash string, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		"INSERT INTO password_resets (user_id, email, token_hash, expires_at) VALUES ($1, $2, $3, $4)",
+		userID, email, tokenHash, expiresAt)
+	return err
+}
+
+func (r *passwordResetRepo) Consume(ctx context.Context, tokenHash string) (int64, string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, "", err
+	}
+	defer tx.Rollback()
+
+	var (
+		userID int64
+		email  string
+	)
+	err = tx.QueryRowContext(ctx,
+		`UPDATE password_resets SET used_at = now()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > now()
+		RETURNING user_id, email`, tokenHash).Scan(&userID, &email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, "", domain.ErrInvalidToken
+	}
+	if err != nil {
+		return 0, "", err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE password_resets SET used_at = now() WHERE user_id = $1 AND used_at IS NULL", userID)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return userID, email, tx.Commit()
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+	"github.com/lib/pq"
+)
+
+type personalTokenRepo struct {
+	db *sql.DB
+}
+
+func NewPersonalTokenRepo(db *sql.DB) domain.PersonalTokenRepository {
+	return &personalTokenRepo{db: db}
+}
+
+const personalTokenColumns = "id, user_id, name, scopes, token_hash, expires_at, last_used_at, created_at"
+
+func scanPersonalToken(row scanner) (*domain.PersonalToken, error) {
+	var t domain.PersonalToken
+	err := row.Scan(&t.ID, &t.UserID, &t.Name, pq.Array(&t.Scopes), &t.TokenHash,
+		&t.ExpiresAt, &t.LastUsedAt, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *personalTokenRepo) Create(ctx context.Context, t *domain.PersonalToken) error {
+	return r.db.QueryRowContext(ctx,
+		`INSERT INTO personal_access_tokens (user_id, name, scopes, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`,
+		t.UserID, t.Name, pq.Array(t.Scopes), t.TokenHash, t.ExpiresAt).Scan(&t.ID, &t.CreatedAt)
+}
+
+func (r *personalTokenRepo) ListForUser(ctx context.Context, userID int64) ([]*domain.PersonalToken, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT "+personalTokenColumns+" FROM personal_access_tokens WHERE user_id = $1 AND revoked_at IS NULL ORDER BY id",
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*domain.PersonalToken
+	for rows.Next() {
+		t, err := scanPersonalToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, rows.Err()
+}
+
+func (r *personalTokenRepo) GetActive(ctx context.Context, tokenHash string) (*domain.PersonalToken, error) {
+	t, err := scanPersonalToken(r.db.QueryRowContext(ctx,
+		`SELECT `+personalTokenColumns+` FROM personal_access_tokens
+		WHERE token_hash = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > now())`,
+		tokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrInvalidToken
+	}
+	return t, err
+}
+
+func (r *personalTokenRepo) Touch(ctx context.Context, id int64, at time.Time) error {
+	// токеном могут пользоваться на каждом запросе, поэтому пишем
+	// не чаще раза в минуту — для "последнего использования" этого хватает
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE personal_access_tokens SET last_used_at = $2
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $2 - interval '1 minute')`,
+		id, at)
+	return err
+}
+
+func (r *personalTokenRepo) Revoke(ctx context.Context, userID, id int64) error {
+	res, err := r.db.ExecContext(ctx,
+		"UPDATE personal_access_tokens SET revoked_at = now() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL",
+		id, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrPersonalTokenNotFound
+	}
+	return nil
+}
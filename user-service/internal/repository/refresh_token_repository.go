@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+)
+
+type refreshTokenRepo struct {
+	db *sql.DB
+}
+
+func NewRefreshTokenRepo(db *sql.DB) domain.RefreshTokenRepository {
+	return &refreshTokenRepo{db: db}
+}
+
+func (r *refreshTokenRepo) Create(ctx context.Context, t *domain.RefreshToken) error {
+	return r.db.QueryRowContext(ctx,
+		"INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at) VALUES ($1, $2, $3, $4) RETURNING id",
+		t.UserID, t.FamilyID, t.TokenHash, t.ExpiresAt).Scan(&t.ID)
+}
+
+func (r *refreshTokenRepo) GetByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	row := r.db.QueryRowContext(ctx,
+		"SELECT id, user_id, family_id, token_hash, expires_at, used_at, revoked_at FROM refresh_tokens WHERE token_hash = $1", hash)
+
+	var t domain.RefreshToken
+	err := row.Scan(&t.ID, &t.UserID, &t.FamilyID, &t.TokenHash, &t.ExpiresAt, &t.UsedAt, &t.RevokedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *refreshTokenRepo) MarkUsed(ctx context.Context, id int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		"UPDATE refresh_tokens SET used_at = now() WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL", id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
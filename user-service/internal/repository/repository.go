@@ -3,9 +3,12 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"errors"
+	"time"
 
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/rbac"
 	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+	"github.com/lib/pq"
 )
 
 type userRepo struct {
@@ -17,34 +20,253 @@ func NewUserRepo(db *sql.DB) domain.UserRepository {
 }
 
 func (r *userRepo) Create(ctx context.Context, u *domain.User) error {
-	err := r.db.QueryRowContext(ctx,
-		"INSERT INTO users (name, email, password) VALUES ($1, $2, $3) RETURNING ID",
-		u.Name, u.Email, u.Password).Scan(&u.ID)
+	return insertUser(ctx, r.db, u)
+}
+
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// insertUser добавляет пользователя u, в том числе внутри транзакции.
+func insertUser(ctx context.Context, q rowQuerier, u *domain.User) error {
+	u.Email = domain.NormalizeEmail(u.Email)
+	err := q.QueryRowContext(ctx,
+		"INSERT INTO users (name, email, password) VALUES ($1, $2, $3) RETURNING ID, role",
+		u.Name, u.Email, u.Password).Scan(&u.ID, &u.Role)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return domain.ErrEmailTaken
+	}
 	return err
 }
 
 func (r *userRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
-	fmt.Println(email)
 	row := r.db.QueryRowContext(ctx,
-		"SELECT id, name, email, password FROM users WHERE email=$1", email)
+		"SELECT id, name, email, password, role, email_verified_at FROM users WHERE email=$1 AND deleted_at IS NULL",
+		domain.NormalizeEmail(email))
 
 	var u domain.User
-	err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Password)
+	err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Password, &u.Role, &u.EmailVerifiedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 	return &u, nil
 }
 
+func (r *userRepo) GetDeletedByEmail(ctx context.Context, email string, since time.Time) (*domain.User, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT id, name, email, password, role, email_verified_at FROM users
+		WHERE email = $1 AND deleted_at >= $2
+		ORDER BY deleted_at DESC LIMIT 1`,
+		domain.NormalizeEmail(email), since)
+
+	var u domain.User
+	err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Password, &u.Role, &u.EmailVerifiedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *userRepo) GetByID(ctx context.Context, userID int64) (*domain.User, error) {
+	row := r.db.QueryRowContext(ctx,
+		"SELECT id, name, email, password, role, email_verified_at FROM users WHERE id = $1 AND deleted_at IS NULL", userID)
+
+	var u domain.User
+	err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Password, &u.Role, &u.EmailVerifiedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *userRepo) GetVisible(ctx context.Context, viewerID int64, ids []int64) ([]*domain.User, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT u.id, u.name, u.email FROM users u
+		WHERE u.id = ANY($2) AND u.deleted_at IS NULL AND (u.id = $1 OR EXISTS (
+			SELECT 1 FROM workspace_members mine
+			JOIN workspace_members theirs ON theirs.workspace_id = mine.workspace_id
+			WHERE mine.user_id = $1 AND theirs.user_id = u.id))
+		ORDER BY u.id`, viewerID, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*domain.User
+	for rows.Next() {
+		var u domain.User
+		if err := rows.Scan(&u.ID, &u.Name, &u.Email); err != nil {
+			return nil, err
+		}
+		users = append(users, &u)
+	}
+	return users, rows.Err()
+}
+
 func (r *userRepo) Profile(ctx context.Context, userID int) (*domain.User, error) {
-	row := r.db.QueryRowContext(ctx, "SELECT id, name, email FROM users WHERE id =$1", userID)
+	row := r.db.QueryRowContext(ctx, "SELECT id, name, email, role, email_verified_at FROM users WHERE id =$1 AND deleted_at IS NULL", userID)
 
 	var user domain.User
-	err := row.Scan(&user.ID, &user.Name, &user.Email)
-
+	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Role, &user.EmailVerifiedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	return &user, nil
 }
+
+func (r *userRepo) Permissions(ctx context.Context, role rbac.Role) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT permission FROM role_permissions WHERE role = $1 ORDER BY permission", role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var permissions []string
+	for rows.Next() {
+		var p string
+		if err := rows.Scan(&p); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, p)
+	}
+	return permissions, rows.Err()
+}
+
+func (r *userRepo) SetRole(ctx context.Context, userID int64, role rbac.Role) error {
+	res, err := r.db.ExecContext(ctx, "UPDATE users SET role = $1 WHERE id = $2", role, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepo) UpdatePassword(ctx context.Context, userID int64, hash string) error {
+	res, err := r.db.ExecContext(ctx, "UPDATE users SET password = $1 WHERE id = $2", hash, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepo) MarkEmailVerified(ctx context.Context, userID int64, email string) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE users SET email_verified_at = now() WHERE id = $1 AND email = $2 AND email_verified_at IS NULL",
+		userID, domain.NormalizeEmail(email))
+	return err
+}
+
+func (r *userRepo) Update(ctx context.Context, u *domain.User) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	u.Email = domain.NormalizeEmail(u.Email)
+	// email_verified_at сбрасывается, только если email действительно сменился
+	var emailChanged bool
+	err = tx.QueryRowContext(ctx,
+		`WITH old AS (SELECT email FROM users WHERE id = $3 AND deleted_at IS NULL FOR UPDATE)
+		UPDATE users u SET name = $1, email = $2,
+			email_verified_at = CASE WHEN u.email = $2 THEN u.email_verified_at END
+		FROM old WHERE u.id = $3
+		RETURNING u.email_verified_at, old.email <> u.email`,
+		u.Name, u.Email, u.ID).Scan(&u.EmailVerifiedAt, &emailChanged)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return domain.ErrEmailTaken
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if emailChanged {
+		// ссылки, отправленные на прежний адрес, не должны действовать для нового
+		for _, query := range []string{
+			"UPDATE email_verifications SET used_at = now() WHERE user_id = $1 AND used_at IS NULL",
+			"UPDATE password_resets SET used_at = now() WHERE user_id = $1 AND used_at IS NULL",
+		} {
+			if _, err := tx.ExecContext(ctx, query, u.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *userRepo) SoftDelete(ctx context.Context, userID int64) error {
+	res, err := r.db.ExecContext(ctx,
+		"UPDATE users SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL", userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepo) Restore(ctx context.Context, userID int64) error {
+	res, err := r.db.ExecContext(ctx,
+		"UPDATE users SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL", userID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return domain.ErrEmailTaken
+	}
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM users WHERE deleted_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+)
+
+type sessionRepo struct {
+	db *sql.DB
+}
+
+func NewSessionRepo(db *sql.DB) domain.SessionRepository {
+	return &sessionRepo{db: db}
+}
+
+func (r *sessionRepo) Create(ctx context.Context, s *domain.Session) error {
+	return r.db.QueryRowContext(ctx,
+		`INSERT INTO sessions (id, user_id, user_agent, ip) VALUES ($1, $2, $3, $4)
+		RETURNING created_at, last_seen_at`,
+		s.ID, s.UserID, s.UserAgent, s.IP).Scan(&s.CreatedAt, &s.LastSeenAt)
+}
+
+func (r *sessionRepo) Touch(ctx context.Context, id, ip string) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE sessions SET last_seen_at = now(), ip = $2 WHERE id = $1", id, ip)
+	return err
+}
+
+func (r *sessionRepo) ListActive(ctx context.Context, userID int64, since time.Time) ([]*domain.Session, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, user_id, user_agent, ip, created_at, last_seen_at FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND last_seen_at > $2
+		ORDER BY last_seen_at DESC`, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*domain.Session
+	for rows.Next() {
+		var s domain.Session
+		if err := rows.Scan(&s.ID, &s.UserID, &s.UserAgent, &s.IP, &s.CreatedAt, &s.LastSeenAt); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &s)
+	}
+	return sessions, rows.Err()
+}
+
+func (r *sessionRepo) Revoke(ctx context.Context, userID int64, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		"UPDATE sessions SET revoked_at = now() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL", id, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrSessionNotFound
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE refresh_tokens SET revoked_at = now() WHERE family_id = $1 AND revoked_at IS NULL", id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *sessionRepo) RevokeAllForUser(ctx context.Context, userID int64) ([]string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx,
+		"UPDATE sessions SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL RETURNING id", userID)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE refresh_tokens SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL", userID)
+	if err != nil {
+		return nil, err
+	}
+	return ids, tx.Commit()
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+)
+
+type workspaceRepo struct {
+	db *sql.DB
+}
+
+func NewWorkspaceRepo(db *sql.DB) domain.WorkspaceRepository {
+	return &workspaceRepo{db: db}
+}
+
+func (r *workspaceRepo) Create(ctx context.Context, w *domain.Workspace) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		"INSERT INTO workspaces (name, created_by) VALUES ($1, $2) RETURNING id",
+		w.Name, w.CreatedBy).Scan(&w.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, $3)",
+		w.ID, w.CreatedBy, domain.WorkspaceOwner)
+	if err != nil {
+		return err
+	}
+	w.Role = domain.WorkspaceOwner
+
+	return tx.Commit()
+}
+
+func (r *workspaceRepo) ListForUser(ctx context.Context, userID int64) ([]*domain.Workspace, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT w.id, w.name, COALESCE(w.created_by, 0), m.role
+		FROM workspaces w JOIN workspace_members m ON m.workspace_id = w.id
+		WHERE m.user_id = $1 ORDER BY w.id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var workspaces []*domain.Workspace
+	for rows.Next() {
+		var w domain.Workspace
+		if err := rows.Scan(&w.ID, &w.Name, &w.CreatedBy, &w.Role); err != nil {
+			return nil, err
+		}
+		workspaces = append(workspaces, &w)
+	}
+	return workspaces, rows.Err()
+}
+
+func (r *workspaceRepo) Membership(ctx context.Context, workspaceID, userID int64) (domain.WorkspaceRole, error) {
+	var role domain.WorkspaceRole
+	err := r.db.QueryRowContext(ctx,
+		"SELECT role FROM workspace_members WHERE workspace_id = $1 AND user_id = $2",
+		workspaceID, userID).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", domain.ErrNotMember
+	}
+	return role, err
+}
+
+func (r *workspaceRepo) Members(ctx context.Context, workspaceID int64) ([]*domain.Member, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT user_id, role FROM workspace_members WHERE workspace_id = $1 ORDER BY joined_at, user_id", workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []*domain.Member
+	for rows.Next() {
+		var m domain.Member
+		if err := rows.Scan(&m.UserID, &m.Role); err != nil {
+			return nil, err
+		}
+		members = append(members, &m)
+	}
+	return members, rows.Err()
+}
+
+func (r *workspaceRepo) SoleOwnerships(ctx context.Context, userID int64) ([]*domain.Workspace, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT w.id, w.name, COALESCE(w.created_by, 0), m.role
+		FROM workspaces w JOIN workspace_members m ON m.workspace_id = w.id
+		WHERE m.user_id = $1 AND m.role = 'owner'
+			AND NOT EXISTS (SELECT 1 FROM workspace_members o
+				WHERE o.workspace_id = w.id AND o.user_id <> $1 AND o.role = 'owner')
+			AND EXISTS (SELECT 1 FROM workspace_members o
+				WHERE o.workspace_id = w.id AND o.user_id <> $1)
+		ORDER BY w.id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var workspaces []*domain.Workspace
+	for rows.Next() {
+		var w domain.Workspace
+		if err := rows.Scan(&w.ID, &w.Name, &w.CreatedBy, &w.Role); err != nil {
+			return nil, err
+		}
+		workspaces = append(workspaces, &w)
+	}
+	return workspaces, rows.Err()
+}
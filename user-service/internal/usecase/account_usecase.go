@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/pkg/utils"
+)
+
+func (uc *userUC) UpdateProfile(ctx context.Context, userID int64, upd domain.ProfileUpdate) (*domain.User, bool, error) {
+	u, err := uc.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if upd.Name != nil {
+		name := strings.TrimSpace(*upd.Name)
+		if name == "" {
+			return nil, false, domain.ErrNameRequired
+		}
+		u.Name = name
+	}
+	emailChanged := false
+	if upd.Email != nil {
+		addr, err := mail.ParseAddress(*upd.Email)
+		if err != nil || addr.Address != *upd.Email {
+			return nil, false, domain.ErrInvalidEmail
+		}
+		emailChanged = !strings.EqualFold(addr.Address, u.Email)
+		u.Email = addr.Address
+	}
+
+	if err := uc.repo.Update(ctx, u); err != nil {
+		return nil, false, err
+	}
+	u.Password = ""
+	u.Permissions, err = uc.repo.Permissions(ctx, u.Role)
+	if err != nil {
+		return nil, false, err
+	}
+	return u, emailChanged, nil
+}
+
+func (uc *userUC) ChangePassword(ctx context.Context, userID int64, current, next string, client domain.ClientInfo) (*domain.TokenPair, error) {
+	if len(next) < domain.MinPasswordLength {
+		return nil, domain.ErrWeakPassword
+	}
+
+	u, err := uc.checkPassword(ctx, userID, current, client.IP)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := utils.HashPassword(next)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.UpdatePassword(ctx, userID, hash); err != nil {
+		return nil, err
+	}
+	// остальные устройства выходят, текущее получает новую сессию
+	if err := revokeAllSessions(ctx, uc.sessions, uc.revoker, userID); err != nil {
+		return nil, err
+	}
+	return uc.startSession(ctx, u, client)
+}
+
+func (uc *userUC) DeleteAccount(ctx context.Context, userID int64, password, ip, tokenID string, expiresAt time.Time) error {
+	if _, err := uc.checkPassword(ctx, userID, password, ip); err != nil {
+		return err
+	}
+
+	if err := uc.repo.SoftDelete(ctx, userID); err != nil {
+		return err
+	}
+	if err := revokeAllSessions(ctx, uc.sessions, uc.revoker, userID); err != nil {
+		return err
+	}
+	if tokenID != "" {
+		return uc.revoker.Revoke(ctx, tokenID, expiresAt)
+	}
+	return nil
+}
+
+func (uc *userUC) PurgeDeleted(ctx context.Context) (int64, error) {
+	return uc.repo.PurgeDeleted(ctx, time.Now().Add(-domain.AccountDeletionGrace))
+}
+
+// checkPassword подтверждает опасное действие текущим паролем. Неверные
+// попытки считаются так же, как при входе, чтобы украденной сессией нельзя
+// было подобрать пароль.
+func (uc *userUC) checkPassword(ctx context.Context, userID int64, password, ip string) (*domain.User, error) {
+	u, err := uc.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.throttle.Reserve(ctx, u.Email, ip); err != nil {
+		return nil, err
+	}
+	if !utils.CheckPasswordHash(password, u.Password) {
+		return nil, domain.ErrInvalidPassword
+	}
+	if err := uc.throttle.Succeed(ctx, u.Email, ip); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
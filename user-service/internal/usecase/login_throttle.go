@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/ratelimit"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+)
+
+var (
+	// AccountLoginPolicy — после 5 неверных паролей задержка 2с, 4с, ... до 15 минут.
+	AccountLoginPolicy = ratelimit.Policy{Free: 5, Base: 2 * time.Second, Max: 15 * time.Minute, Window: time.Hour}
+	// IPLoginPolicy мягче: за одним адресом может быть много пользователей.
+	IPLoginPolicy = ratelimit.Policy{Free: 20, Base: time.Second, Max: 15 * time.Minute, Window: time.Hour}
+)
+
+type loginThrottle struct {
+	accounts ratelimit.Limiter
+	ips      ratelimit.Limiter
+}
+
+func NewLoginThrottle(accounts, ips ratelimit.Limiter) domain.LoginThrottle {
+	return &loginThrottle{accounts: accounts, ips: ips}
+}
+
+func (t *loginThrottle) Reserve(ctx context.Context, email, ip string) error {
+	account := accountKey(email)
+	wait, err := t.accounts.Reserve(ctx, account)
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		return &domain.RateLimitError{RetryAfter: wait}
+	}
+	if ip == "" {
+		return nil
+	}
+
+	wait, err = t.ips.Reserve(ctx, ip)
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		// попытка так и не состоялась — возвращаем её аккаунту
+		if err := t.accounts.Refund(ctx, account); err != nil {
+			return err
+		}
+		return &domain.RateLimitError{RetryAfter: wait}
+	}
+	return nil
+}
+
+func (t *loginThrottle) Succeed(ctx context.Context, email, ip string) error {
+	if err := t.accounts.Reset(ctx, accountKey(email)); err != nil {
+		return err
+	}
+	if ip != "" {
+		return t.ips.Refund(ctx, ip)
+	}
+	return nil
+}
+
+// accountKey считает счётчик и для несуществующих email,
+// чтобы по блокировке нельзя было узнать, зарегистрирован ли адрес.
+// Email приводится так же, как при поиске пользователя.
+func accountKey(email string) string {
+	return domain.NormalizeEmail(email)
+}
@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/pkg/totp"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/pkg/utils"
+)
+
+const (
+	totpIssuer = "Taqsym"
+	// challengeTTL — сколько есть времени ввести код после пароля.
+	challengeTTL = 5 * time.Minute
+	// maxChallengeAttempts — сколько неверных кодов можно ввести на один вход.
+	maxChallengeAttempts = 5
+	recoveryCodeCount    = 10
+)
+
+func (uc *userUC) createChallenge(ctx context.Context, userID int64) (string, error) {
+	token, hash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	if err := uc.mfa.CreateChallenge(ctx, userID, hash, time.Now().Add(challengeTTL)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (uc *userUC) VerifySecondFactor(ctx context.Context, challengeToken, code string, client domain.ClientInfo) (*domain.TokenPair, error) {
+	ip := client.IP
+	hash := utils.HashToken(challengeToken)
+	userID, err := uc.mfa.ChallengeUser(ctx, hash, maxChallengeAttempts)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := uc.repo.Profile(ctx, int(userID))
+	if err != nil {
+		return nil, err
+	}
+	// коды считаются вместе с паролями этого аккаунта
+	if err := uc.throttle.Reserve(ctx, u.Email, ip); err != nil {
+		return nil, err
+	}
+
+	m, err := uc.mfa.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkCode(ctx, userID, m, code); err != nil {
+		if errors.Is(err, domain.ErrInvalidCode) {
+			if err := uc.mfa.FailChallenge(ctx, hash); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
+	ok, err := uc.mfa.CompleteChallenge(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domain.ErrInvalidToken
+	}
+
+	if err := uc.throttle.Succeed(ctx, u.Email, ip); err != nil {
+		return nil, err
+	}
+	return uc.startSession(ctx, u, client)
+}
+
+func (uc *userUC) EnrollTOTP(ctx context.Context, userID int64) (*domain.TOTPEnrollment, error) {
+	u, err := uc.repo.Profile(ctx, int(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.mfa.SetPendingSecret(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+
+	return &domain.TOTPEnrollment{
+		Secret: secret,
+		URI:    totp.URI(totpIssuer, u.Email, secret),
+	}, nil
+}
+
+func (uc *userUC) ConfirmTOTP(ctx context.Context, userID int64, code, ip string) ([]string, error) {
+	m, err := uc.mfa.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if m.EnabledAt != nil {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+	if m.Secret == "" {
+		return nil, domain.ErrMFANotEnrolled
+	}
+
+	err = uc.throttledCode(ctx, userID, ip, func() error {
+		step, ok := totp.Validate(m.Secret, code, time.Now(), m.LastStep)
+		if !ok {
+			return domain.ErrInvalidCode
+		}
+		_, err := uc.mfa.UseStep(ctx, userID, step)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, utils.HashToken(normalizeRecoveryCode(code)))
+	}
+
+	if err := uc.mfa.Enable(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (uc *userUC) DisableTOTP(ctx context.Context, userID int64, code, ip string) error {
+	m, err := uc.mfa.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if m.EnabledAt == nil {
+		return domain.ErrMFANotEnrolled
+	}
+	err = uc.throttledCode(ctx, userID, ip, func() error {
+		return uc.checkCode(ctx, userID, m, code)
+	})
+	if err != nil {
+		return err
+	}
+	return uc.mfa.Disable(ctx, userID)
+}
+
+// throttledCode проверяет код через check с ограничением перебора: как и
+// при входе, коды считаются вместе с паролями аккаунта.
+func (uc *userUC) throttledCode(ctx context.Context, userID int64, ip string, check func() error) error {
+	u, err := uc.repo.Profile(ctx, int(userID))
+	if err != nil {
+		return err
+	}
+	if err := uc.throttle.Reserve(ctx, u.Email, ip); err != nil {
+		return err
+	}
+	if err := check(); err != nil {
+		return err
+	}
+	return uc.throttle.Succeed(ctx, u.Email, ip)
+}
+
+// checkCode принимает код TOTP или код восстановления. Каждый код
+// срабатывает только один раз.
+func (uc *userUC) checkCode(ctx context.Context, userID int64, m *domain.MFA, code string) error {
+	if m.EnabledAt == nil {
+		return domain.ErrMFANotEnrolled
+	}
+
+	if step, ok := totp.Validate(m.Secret, code, time.Now(), m.LastStep); ok {
+		fresh, err := uc.mfa.UseStep(ctx, userID, step)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return domain.ErrInvalidCode
+		}
+		return nil
+	}
+
+	used, err := uc.mfa.UseRecoveryCode(ctx, userID, utils.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return domain.ErrInvalidCode
+	}
+	return nil
+}
+
+// recoveryAlphabet без похожих символов: 0/o, 1/l/i.
+const recoveryAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// generateRecoveryCode возвращает код вида xxxxx-xxxxx.
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := make([]byte, 0, 11)
+	for i, v := range b {
+		if i == 5 {
+			code = append(code, '-')
+		}
+		// небольшое смещение распределения из-за % здесь несущественно
+		code = append(code, recoveryAlphabet[int(v)%len(recoveryAlphabet)])
+	}
+	return string(code), nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/pkg/mailer"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/pkg/utils"
+)
+
+// passwordResetTTL — сколько действует ссылка сброса пароля.
+const passwordResetTTL = time.Hour
+
+type passwordUC struct {
+	users    domain.UserRepository
+	resets   domain.PasswordResetRepository
+	sessions domain.SessionRepository
+	revoker  domain.TokenRevoker
+	mail     mailer.Mailer
+	// resetURL — адрес страницы сброса, токен дописывается в конец.
+	resetURL string
+}
+
+func NewPasswordUseCase(users domain.UserRepository, resets domain.PasswordResetRepository, sessions domain.SessionRepository, revoker domain.TokenRevoker, mail mailer.Mailer, resetURL string) domain.PasswordUseCase {
+	return &passwordUC{users: users, resets: resets, sessions: sessions, revoker: revoker, mail: mail, resetURL: resetURL}
+}
+
+func (uc *passwordUC) RequestReset(ctx context.Context, email string) error {
+	u, err := uc.users.GetByEmail(ctx, email)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil // не раскрываем, зарегистрирован ли email
+	}
+	if err != nil {
+		return err
+	}
+
+	token, hash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+	if err := uc.resets.Create(ctx, u.ID, u.Email, hash, time.Now().Add(passwordResetTTL)); err != nil {
+		return err
+	}
+
+	err = uc.mail.Send(ctx, mailer.Message{
+		To:      u.Email,
+		Subject: "Сброс пароля",
+		Body: fmt.Sprintf("Здравствуйте, %s!\r\n\r\n"+
+			"Чтобы задать новый пароль, перейдите по ссылке:\r\n%s%s\r\n\r\n"+
+			"Ссылка действует %d минут. Если вы не запрашивали сброс, просто проигнорируйте это письмо.\r\n",
+			u.Name, uc.resetURL, token, int(passwordResetTTL.Minutes())),
+	})
+	if err != nil {
+		// ошибку отправки не возвращаем, иначе по ней можно узнать, есть ли пользователь
+		log.Printf("не удалось отправить письмо сброса пароля пользователю %d: %v", u.ID, err)
+	}
+	return nil
+}
+
+func (uc *passwordUC) Reset(ctx context.Context, token, newPassword string) error {
+	if len(newPassword) < domain.MinPasswordLength {
+		return domain.ErrWeakPassword
+	}
+
+	userID, email, err := uc.resets.Consume(ctx, utils.HashToken(token))
+	if err != nil {
+		return err
+	}
+
+	hash, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := uc.users.UpdatePassword(ctx, userID, hash); err != nil {
+		return err
+	}
+	// ссылка пришла на email, значит адрес принадлежит пользователю —
+	// если только с тех пор он не сменился
+	if err := uc.users.MarkEmailVerified(ctx, userID, email); err != nil {
+		return err
+	}
+
+	return revokeAllSessions(ctx, uc.sessions, uc.revoker, userID)
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/grpcauth"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/rbac"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/pkg/utils"
+)
+
+type personalTokenUC struct {
+	users  domain.UserRepository
+	tokens domain.PersonalTokenRepository
+}
+
+func NewPersonalTokenUseCase(users domain.UserRepository, tokens domain.PersonalTokenRepository) domain.PersonalTokenUseCase {
+	return &personalTokenUC{users: users, tokens: tokens}
+}
+
+func (uc *personalTokenUC) Create(ctx context.Context, userID int64, name string, scopes []string, expiresAt *time.Time) (*domain.PersonalToken, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", domain.ErrNameRequired
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", domain.ErrInvalidExpiry
+	}
+	if len(scopes) == 0 {
+		return nil, "", fmt.Errorf("%w: at least one scope is required", domain.ErrInvalidScope)
+	}
+
+	// выдать токену можно только то, что пользователь может сам
+	u, err := uc.users.Profile(ctx, int(userID))
+	if err != nil {
+		return nil, "", err
+	}
+	permissions, err := uc.users.Permissions(ctx, u.Role)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, scope := range scopes {
+		if !rbac.Has(permissions, scope) {
+			return nil, "", fmt.Errorf("%w: %q", domain.ErrInvalidScope, scope)
+		}
+	}
+
+	secret, _, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, "", err
+	}
+	token := grpcauth.PersonalTokenPrefix + secret
+	t := &domain.PersonalToken{
+		UserID:    userID,
+		Name:      name,
+		Scopes:    scopes,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: expiresAt,
+	}
+	if err := uc.tokens.Create(ctx, t); err != nil {
+		return nil, "", err
+	}
+	return t, token, nil
+}
+
+func (uc *personalTokenUC) List(ctx context.Context, userID int64) ([]*domain.PersonalToken, error) {
+	return uc.tokens.ListForUser(ctx, userID)
+}
+
+func (uc *personalTokenUC) Revoke(ctx context.Context, userID, id int64) error {
+	return uc.tokens.Revoke(ctx, userID, id)
+}
+
+func (uc *personalTokenUC) Authenticate(ctx context.Context, token string) (*domain.PersonalToken, *domain.User, error) {
+	if !strings.HasPrefix(token, grpcauth.PersonalTokenPrefix) {
+		return nil, nil, domain.ErrInvalidToken
+	}
+	t, err := uc.tokens.GetActive(ctx, utils.HashToken(token))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// удалённый пользователь не находится, его токены перестают работать
+	u, err := uc.users.Profile(ctx, int(t.UserID))
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// роль могли понизить после выпуска токена
+	permissions, err := uc.users.Permissions(ctx, u.Role)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, scope := range t.Scopes {
+		if rbac.Has(permissions, scope) {
+			u.Permissions = append(u.Permissions, scope)
+		}
+	}
+
+	if err := uc.tokens.Touch(ctx, t.ID, time.Now()); err != nil {
+		// из-за отметки об использовании запрос не отклоняем
+		log.Printf("не удалось обновить last_used_at токена %d: %v", t.ID, err)
+	}
+	return t, u, nil
+}
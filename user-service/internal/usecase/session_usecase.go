@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/revocation"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/pkg/utils"
+)
+
+// maxUserAgentLength — сколько байт User-Agent храним в сессии.
+const maxUserAgentLength = 512
+
+// startSession открывает сессию для нового входа и выдаёт её токены.
+func (uc *userUC) startSession(ctx context.Context, u *domain.User, client domain.ClientInfo) (*domain.TokenPair, error) {
+	// каждый вход начинает новое семейство refresh токенов
+	id, err := utils.RandomID(16)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.sessions.Create(ctx, &domain.Session{
+		ID:        id,
+		UserID:    u.ID,
+		UserAgent: truncateUTF8(client.UserAgent, maxUserAgentLength),
+		IP:        client.IP,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uc.issueTokens(ctx, u, id)
+}
+
+func (uc *userUC) ListSessions(ctx context.Context, userID int64) ([]*domain.Session, error) {
+	// refresh token живёт RefreshTokenTTL с последнего обновления,
+	// сессии старше уже не продлить
+	return uc.sessions.ListActive(ctx, userID, time.Now().Add(-utils.RefreshTokenTTL))
+}
+
+func (uc *userUC) RevokeSession(ctx context.Context, userID int64, sessionID string) error {
+	if err := uc.sessions.Revoke(ctx, userID, sessionID); err != nil {
+		return err
+	}
+	return revokeAccessTokens(ctx, uc.revoker, sessionID)
+}
+
+// revokeAllSessions завершает все сессии пользователя вместе с их access token.
+func revokeAllSessions(ctx context.Context, sessions domain.SessionRepository, revoker domain.TokenRevoker, userID int64) error {
+	ids, err := sessions.RevokeAllForUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return revokeAccessTokens(ctx, revoker, ids...)
+}
+
+// revokeAccessTokens отзывает access token сессий. Новее AccessTokenTTL
+// токенов у отозванной сессии быть не может, дальше запись не нужна.
+func revokeAccessTokens(ctx context.Context, revoker domain.TokenRevoker, sessionIDs ...string) error {
+	expiresAt := time.Now().Add(utils.AccessTokenTTL)
+	for _, id := range sessionIDs {
+		if err := revoker.Revoke(ctx, revocation.SessionKey(id), expiresAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[:n]
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
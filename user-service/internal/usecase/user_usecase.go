@@ -3,20 +3,36 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"time"
 
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/jwks"
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/rbac"
 	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
 	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/pkg/utils"
 )
 
 type userUC struct {
-	repo domain.UserRepository
+	repo     domain.UserRepository
+	tokens   domain.RefreshTokenRepository
+	sessions domain.SessionRepository
+	revoker  domain.TokenRevoker
+	mfa      domain.MFARepository
+	throttle domain.LoginThrottle
+	keys     *jwks.KeySet
+	// requireVerified запрещает вход, пока email не подтверждён.
+	requireVerified bool
 }
 
-func NewUserUseCase(repo domain.UserRepository) domain.UserUseCase {
-	return &userUC{repo: repo}
+func NewUserUseCase(repo domain.UserRepository, tokens domain.RefreshTokenRepository, sessions domain.SessionRepository, revoker domain.TokenRevoker, mfa domain.MFARepository, throttle domain.LoginThrottle, keys *jwks.KeySet, requireVerified bool) domain.UserUseCase {
+	return &userUC{repo: repo, tokens: tokens, sessions: sessions, revoker: revoker, mfa: mfa, throttle: throttle, keys: keys, requireVerified: requireVerified}
 }
 
 func (uc *userUC) Register(ctx context.Context, u *domain.User) error {
+	if len(u.Password) < domain.MinPasswordLength {
+		return domain.ErrWeakPassword
+	}
 	hashed, err := utils.HashPassword(u.Password)
 	if err != nil {
 		return err
@@ -25,20 +41,194 @@ func (uc *userUC) Register(ctx context.Context, u *domain.User) error {
 	return uc.repo.Create(ctx, u)
 }
 
-func (uc *userUC) Login(ctx context.Context, email, password string) (string, error) {
+func (uc *userUC) Login(ctx context.Context, email, password string, client domain.ClientInfo) (*domain.LoginResult, error) {
+	ip := client.IP
+	// попытку учитываем до bcrypt: он дорогой, а заблокированные
+	// и параллельные попытки сверх лимита до него не доходят
+	if err := uc.throttle.Reserve(ctx, email, ip); err != nil {
+		return nil, err
+	}
+
 	u, err := uc.repo.GetByEmail(ctx, email)
+	deleted := false
+	if errors.Is(err, domain.ErrUserNotFound) {
+		u, err = uc.repo.GetDeletedByEmail(ctx, email, time.Now().Add(-domain.AccountDeletionGrace))
+		deleted = err == nil
+	}
+	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+		return nil, err
+	}
+	if err != nil || !utils.CheckPasswordHash(password, u.Password) {
+		return nil, domain.ErrInvalidCredentials
+	}
+	// проверяем после пароля, чтобы не раскрывать состояние чужих аккаунтов
+	if uc.requireVerified && u.EmailVerifiedAt == nil {
+		return nil, domain.ErrEmailNotVerified
+	}
+	if deleted {
+		// вход отменяет удаление; без второго фактора токенов всё равно не выдадим
+		if err := uc.repo.Restore(ctx, u.ID); err != nil {
+			return nil, err
+		}
+		log.Printf("аккаунт %d восстановлен входом", u.ID)
+	}
+
+	m, err := uc.mfa.Get(ctx, u.ID)
 	if err != nil {
-		return "", errors.New("invalid credentials")
+		return nil, err
+	}
+	if m.EnabledAt != nil {
+		// счётчик аккаунта не обнуляем до второго фактора,
+		// иначе с известным паролем можно было бы перебирать коды
+		challenge, err := uc.createChallenge(ctx, u.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.LoginResult{ChallengeToken: challenge}, nil
 	}
 
-	if !utils.CheckPasswordHash(password, u.Password) {
-		return "", errors.New("invalid credentials")
+	if err := uc.throttle.Succeed(ctx, email, ip); err != nil {
+		return nil, err
 	}
 
-	return utils.GenerateToken(u.ID, u.Email)
+	tokens, err := uc.startSession(ctx, u, client)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.LoginResult{Tokens: tokens}, nil
 }
 
 func (uc *userUC) Profile(ctx context.Context, userID int) (*domain.User, error) {
 	user, err := uc.repo.Profile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Permissions, err = uc.repo.Permissions(ctx, user.Role)
 	return user, err
 }
+
+func (uc *userUC) GetUsers(ctx context.Context, viewerID int64, ids []int64) ([]*domain.User, error) {
+	unique := make([]int64, 0, len(ids))
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if id > 0 && !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return nil, nil
+	}
+	if len(unique) > domain.MaxUsersPerLookup {
+		return nil, fmt.Errorf("%w: at most %d ids per request", domain.ErrTooManyUsers, domain.MaxUsersPerLookup)
+	}
+	return uc.repo.GetVisible(ctx, viewerID, unique)
+}
+
+func (uc *userUC) SetRole(ctx context.Context, userID int64, role rbac.Role) error {
+	if !role.Valid() {
+		return fmt.Errorf("%w: %q", domain.ErrInvalidRole, role)
+	}
+	return uc.repo.SetRole(ctx, userID, role)
+}
+
+func (uc *userUC) Refresh(ctx context.Context, refreshToken string, client domain.ClientInfo) (*domain.TokenPair, error) {
+	t, err := uc.tokens.GetByHash(ctx, utils.HashToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if t.RevokedAt != nil || time.Now().After(t.ExpiresAt) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	fresh, err := uc.tokens.MarkUsed(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		// токен уже обменивали: его украли либо у клиента, либо у нас.
+		// Завершаем всю сессию, владельцу придётся войти заново.
+		if err := uc.endSession(ctx, t.UserID, t.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInvalidToken
+	}
+
+	if err := uc.sessions.Touch(ctx, t.FamilyID, client.IP); err != nil {
+		return nil, err
+	}
+
+	u, err := uc.repo.Profile(ctx, int(t.UserID))
+	if err != nil {
+		return nil, err
+	}
+	return uc.issueTokens(ctx, u, t.FamilyID)
+}
+
+func (uc *userUC) Logout(ctx context.Context, userID int64, tokenID, sessionID string, expiresAt time.Time, refreshToken string) error {
+	if tokenID != "" {
+		if err := uc.revoker.Revoke(ctx, tokenID, expiresAt); err != nil {
+			return err
+		}
+	}
+	if sessionID != "" {
+		if err := uc.endSession(ctx, userID, sessionID); err != nil {
+			return err
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+	t, err := uc.tokens.GetByHash(ctx, utils.HashToken(refreshToken))
+	if errors.Is(err, domain.ErrInvalidToken) {
+		return nil // неизвестный токен: выходить уже неоткуда
+	}
+	if err != nil {
+		return err
+	}
+	if t.UserID != userID {
+		return domain.ErrInvalidToken
+	}
+	return uc.endSession(ctx, userID, t.FamilyID)
+}
+
+// endSession завершает сессию, если она ещё не завершена.
+func (uc *userUC) endSession(ctx context.Context, userID int64, sessionID string) error {
+	err := uc.RevokeSession(ctx, userID, sessionID)
+	if errors.Is(err, domain.ErrSessionNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (uc *userUC) issueTokens(ctx context.Context, u *domain.User, familyID string) (*domain.TokenPair, error) {
+	permissions, err := uc.repo.Permissions(ctx, u.Role)
+	if err != nil {
+		return nil, err
+	}
+	access, err := utils.GenerateToken(uc.keys, u.ID, u.Email, string(u.Role), permissions, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh, hash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	err = uc.tokens.Create(ctx, &domain.RefreshToken{
+		UserID:    u.ID,
+		FamilyID:  familyID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(utils.RefreshTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		ExpiresIn:    utils.AccessTokenTTL,
+	}, nil
+}
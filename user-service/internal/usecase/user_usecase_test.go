@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+)
+
+// Короткий пароль отклоняется до хеширования и обращения к базе,
+// поэтому репозитории здесь не нужны.
+func TestRegisterRejectsShortPassword(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		register func(u *domain.User) error
+	}{
+		{"register", func(u *domain.User) error {
+			return (&userUC{}).Register(ctx, u)
+		}},
+		{"register by invitation", func(u *domain.User) error {
+			_, err := (&workspaceUC{}).RegisterInvited(ctx, u, "token")
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &domain.User{Email: "a@example.com", Password: "1234567"}
+			if err := tt.register(u); !errors.Is(err, domain.ErrWeakPassword) {
+				t.Errorf("err = %v, want ErrWeakPassword", err)
+			}
+			if u.Password != "1234567" {
+				t.Errorf("password was hashed before the length check")
+			}
+		})
+	}
+}
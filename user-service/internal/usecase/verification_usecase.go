@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/pkg/mailer"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/pkg/utils"
+)
+
+// emailVerificationTTL — сколько действует ссылка подтверждения email.
+const emailVerificationTTL = 24 * time.Hour
+
+type verificationUC struct {
+	users         domain.UserRepository
+	verifications domain.EmailVerificationRepository
+	mail          mailer.Mailer
+	// verifyURL — адрес страницы подтверждения, токен дописывается в конец.
+	verifyURL string
+}
+
+func NewVerificationUseCase(users domain.UserRepository, verifications domain.EmailVerificationRepository, mail mailer.Mailer, verifyURL string) domain.VerificationUseCase {
+	return &verificationUC{users: users, verifications: verifications, mail: mail, verifyURL: verifyURL}
+}
+
+func (uc *verificationUC) Send(ctx context.Context, u *domain.User) error {
+	token, hash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+	if err := uc.verifications.Create(ctx, u.ID, u.Email, hash, time.Now().Add(emailVerificationTTL)); err != nil {
+		return err
+	}
+
+	return uc.mail.Send(ctx, mailer.Message{
+		To:      u.Email,
+		Subject: "Подтверждение email",
+		Body: fmt.Sprintf("Здравствуйте, %s!\r\n\r\n"+
+			"Подтвердите адрес, перейдя по ссылке:\r\n%s%s\r\n\r\n"+
+			"Ссылка действует %d часа. Если вы не регистрировались, просто проигнорируйте это письмо.\r\n",
+			u.Name, uc.verifyURL, token, int(emailVerificationTTL.Hours())),
+	})
+}
+
+func (uc *verificationUC) Verify(ctx context.Context, token string) error {
+	userID, email, err := uc.verifications.Consume(ctx, utils.HashToken(token))
+	if err != nil {
+		return err
+	}
+	return uc.users.MarkEmailVerified(ctx, userID, email)
+}
+
+func (uc *verificationUC) Resend(ctx context.Context, email string) error {
+	u, err := uc.users.GetByEmail(ctx, email)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil // не раскрываем, зарегистрирован ли email
+	}
+	if err != nil {
+		return err
+	}
+	if u.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	if err := uc.Send(ctx, u); err != nil {
+		log.Printf("не удалось отправить письмо подтверждения пользователю %d: %v", u.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/internal/domain"
+	"github.com/Murodkadirkhanoff/taqsym.uz/user-service/pkg/utils"
+)
+
+// invitationTTL — сколько действует приглашение в пространство.
+const invitationTTL = 7 * 24 * time.Hour
+
+type workspaceUC struct {
+	repo        domain.WorkspaceRepository
+	invitations domain.InvitationRepository
+}
+
+func NewWorkspaceUseCase(repo domain.WorkspaceRepository, invitations domain.InvitationRepository) domain.WorkspaceUseCase {
+	return &workspaceUC{repo: repo, invitations: invitations}
+}
+
+func (uc *workspaceUC) Create(ctx context.Context, userID int64, name string) (*domain.Workspace, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domain.ErrNameRequired
+	}
+
+	w := &domain.Workspace{Name: name, CreatedBy: userID}
+	if err := uc.repo.Create(ctx, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (uc *workspaceUC) List(ctx context.Context, userID int64) ([]*domain.Workspace, error) {
+	return uc.repo.ListForUser(ctx, userID)
+}
+
+func (uc *workspaceUC) Membership(ctx context.Context, actorID, workspaceID, userID int64) (domain.WorkspaceRole, error) {
+	if actorID != userID {
+		// о составе пространства можно спрашивать, только состоя в нём
+		if _, err := uc.repo.Membership(ctx, workspaceID, actorID); err != nil {
+			return "", err
+		}
+	}
+	return uc.repo.Membership(ctx, workspaceID, userID)
+}
+
+func (uc *workspaceUC) Members(ctx context.Context, actorID, workspaceID int64) ([]*domain.Member, error) {
+	if _, err := uc.repo.Membership(ctx, workspaceID, actorID); err != nil {
+		return nil, err
+	}
+	return uc.repo.Members(ctx, workspaceID)
+}
+
+func (uc *workspaceUC) CheckCanLeave(ctx context.Context, userID int64) error {
+	owned, err := uc.repo.SoleOwnerships(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(owned) > 0 {
+		return fmt.Errorf("%w: %q", domain.ErrSoleOwner, owned[0].Name)
+	}
+	return nil
+}
+
+func (uc *workspaceUC) Invite(ctx context.Context, actorID, workspaceID int64, email string, role domain.WorkspaceRole) (*domain.Invitation, string, error) {
+	if err := uc.requireAdmin(ctx, actorID, workspaceID); err != nil {
+		return nil, "", err
+	}
+	if role == "" {
+		role = domain.WorkspaceMember
+	}
+	if role != domain.WorkspaceMember && role != domain.WorkspaceAdmin {
+		return nil, "", fmt.Errorf("%w: can not invite with role %q", domain.ErrInvalidRole, role)
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %q", domain.ErrInvalidEmail, email)
+	}
+
+	token, hash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, "", err
+	}
+	inv := &domain.Invitation{
+		WorkspaceID: workspaceID,
+		Email:       domain.NormalizeEmail(addr.Address),
+		Role:        role,
+		TokenHash:   hash,
+		InvitedBy:   actorID,
+		ExpiresAt:   time.Now().Add(invitationTTL),
+	}
+	if err := uc.invitations.Create(ctx, inv); err != nil {
+		return nil, "", err
+	}
+	return inv, token, nil
+}
+
+func (uc *workspaceUC) RegisterInvited(ctx context.Context, u *domain.User, token string) (*domain.Invitation, error) {
+	if len(u.Password) < domain.MinPasswordLength {
+		return nil, domain.ErrWeakPassword
+	}
+	hashed, err := utils.HashPassword(u.Password)
+	if err != nil {
+		return nil, err
+	}
+	u.Password = hashed
+	return uc.invitations.AcceptAsNewUser(ctx, utils.HashToken(token), u)
+}
+
+func (uc *workspaceUC) AcceptInvitation(ctx context.Context, userID int64, token string) (*domain.Invitation, error) {
+	return uc.invitations.Accept(ctx, utils.HashToken(token), userID)
+}
+
+func (uc *workspaceUC) RevokeInvitation(ctx context.Context, actorID, invitationID int64) error {
+	inv, err := uc.invitations.GetByID(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if err := uc.requireAdmin(ctx, actorID, inv.WorkspaceID); err != nil {
+		if errors.Is(err, domain.ErrNotMember) {
+			// не раскрываем чужие приглашения
+			return domain.ErrInvitationNotFound
+		}
+		return err
+	}
+	return uc.invitations.Revoke(ctx, invitationID)
+}
+
+func (uc *workspaceUC) ListInvitations(ctx context.Context, actorID, workspaceID int64) ([]*domain.Invitation, error) {
+	if err := uc.requireAdmin(ctx, actorID, workspaceID); err != nil {
+		return nil, err
+	}
+	return uc.invitations.ListPending(ctx, workspaceID)
+}
+
+// requireAdmin проверяет, что пользователь owner или admin пространства.
+func (uc *workspaceUC) requireAdmin(ctx context.Context, userID, workspaceID int64) error {
+	role, err := uc.repo.Membership(ctx, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if role != domain.WorkspaceOwner && role != domain.WorkspaceAdmin {
+		return domain.ErrWorkspaceForbidden
+	}
+	return nil
+}
@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN role;
+DROP TABLE role_permissions;
+DROP TABLE roles;
@@ -0,0 +1,35 @@
+CREATE TABLE roles (
+    name TEXT PRIMARY KEY
+);
+
+CREATE TABLE role_permissions (
+    role TEXT NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+    permission TEXT NOT NULL,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name) VALUES ('admin'), ('manager'), ('member'), ('viewer');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'tasks:read'),
+    ('admin', 'tasks:create'),
+    ('admin', 'tasks:update'),
+    ('admin', 'tasks:delete'),
+    ('admin', 'tasks:assign'),
+    ('admin', 'users:read'),
+    ('admin', 'users:manage'),
+    ('manager', 'tasks:read'),
+    ('manager', 'tasks:create'),
+    ('manager', 'tasks:update'),
+    ('manager', 'tasks:delete'),
+    ('manager', 'tasks:assign'),
+    ('manager', 'users:read'),
+    ('member', 'tasks:read'),
+    ('member', 'tasks:create'),
+    ('member', 'tasks:update'),
+    ('member', 'tasks:delete'),
+    ('member', 'tasks:assign'),
+    ('viewer', 'tasks:read');
+
+ALTER TABLE users
+    ADD COLUMN role TEXT NOT NULL DEFAULT 'member' REFERENCES roles (name);
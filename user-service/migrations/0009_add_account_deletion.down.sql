@@ -0,0 +1,17 @@
+ALTER TABLE workspace_invitations
+    DROP CONSTRAINT workspace_invitations_accepted_by_fkey,
+    ADD CONSTRAINT workspace_invitations_accepted_by_fkey
+        FOREIGN KEY (accepted_by) REFERENCES users (id),
+    DROP CONSTRAINT workspace_invitations_invited_by_fkey,
+    ADD CONSTRAINT workspace_invitations_invited_by_fkey
+        FOREIGN KEY (invited_by) REFERENCES users (id);
+
+-- пространства удалённых пользователей не даст вернуть NOT NULL
+ALTER TABLE workspaces
+    DROP CONSTRAINT workspaces_created_by_fkey,
+    ADD CONSTRAINT workspaces_created_by_fkey
+        FOREIGN KEY (created_by) REFERENCES users (id),
+    ALTER COLUMN created_by SET NOT NULL;
+
+DROP INDEX idx_users_deleted_at;
+ALTER TABLE users DROP COLUMN deleted_at;
@@ -0,0 +1,19 @@
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX idx_users_deleted_at ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- после окончательного удаления пользователя его пространства остаются,
+-- а выписанные им приглашения пропадают
+ALTER TABLE workspaces
+    ALTER COLUMN created_by DROP NOT NULL,
+    DROP CONSTRAINT workspaces_created_by_fkey,
+    ADD CONSTRAINT workspaces_created_by_fkey
+        FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL;
+
+ALTER TABLE workspace_invitations
+    DROP CONSTRAINT workspace_invitations_invited_by_fkey,
+    ADD CONSTRAINT workspace_invitations_invited_by_fkey
+        FOREIGN KEY (invited_by) REFERENCES users (id) ON DELETE CASCADE,
+    DROP CONSTRAINT workspace_invitations_accepted_by_fkey,
+    ADD CONSTRAINT workspace_invitations_accepted_by_fkey
+        FOREIGN KEY (accepted_by) REFERENCES users (id) ON DELETE SET NULL;
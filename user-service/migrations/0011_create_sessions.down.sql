@@ -0,0 +1,2 @@
+ALTER TABLE refresh_tokens DROP CONSTRAINT refresh_tokens_family_id_fkey;
+DROP TABLE sessions;
@@ -0,0 +1,2 @@
+ALTER TABLE password_resets DROP COLUMN email;
+ALTER TABLE email_verifications DROP COLUMN email;
@@ -0,0 +1,11 @@
+-- ссылки подтверждения и сброса действуют только для адреса, на который
+-- отправлены. Для старых ссылок адрес неизвестен, поэтому гасим их:
+-- новую ссылку всегда можно запросить ещё раз
+UPDATE email_verifications SET used_at = now() WHERE used_at IS NULL;
+UPDATE password_resets SET used_at = now() WHERE used_at IS NULL;
+
+ALTER TABLE email_verifications ADD COLUMN email TEXT NOT NULL DEFAULT '';
+ALTER TABLE email_verifications ALTER COLUMN email DROP DEFAULT;
+
+ALTER TABLE password_resets ADD COLUMN email TEXT NOT NULL DEFAULT '';
+ALTER TABLE password_resets ALTER COLUMN email DROP DEFAULT;
@@ -0,0 +1,9 @@
+-- email хранится в нижнем регистре (domain.NormalizeEmail), иначе вход
+-- и ограничение перебора расходятся для Foo@x.uz и foo@x.uz. Если есть
+-- адреса, различающиеся только регистром, миграция упадёт на уникальном
+-- индексе: такие аккаунты нужно объединить или переименовать вручную
+UPDATE users SET email = lower(email) WHERE email <> lower(email);
+UPDATE email_verifications SET email = lower(email) WHERE email <> lower(email);
+UPDATE password_resets SET email = lower(email) WHERE email <> lower(email);
+
+ALTER TABLE users ADD CONSTRAINT users_email_lower CHECK (email = lower(email));
@@ -0,0 +1,3 @@
+-- упадёт, если адрес удалённого аккаунта уже занят новым
+DROP INDEX users_email_active;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
@@ -0,0 +1,5 @@
+-- email уникален только среди неудалённых аккаунтов: пока удалённый ждёт
+-- окончательного удаления, адрес можно занять новым аккаунтом. Восстановить
+-- старый тогда не выйдет, пока новый не освободит адрес
+ALTER TABLE users DROP CONSTRAINT users_email_key;
+CREATE UNIQUE INDEX users_email_active ON users (email) WHERE deleted_at IS NULL;
@@ -7,16 +7,8 @@ import (
 	_ "github.com/lib/pq"
 )
 
-func NewPostgres() (*sql.DB, error) {
-	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		"postgres", "password", "postgres", "5432", "postgres")
-
-	// username	postgres	Имя пользователя базы данных
-	// password	password	Пароль к базе данных
-	// host	postgres	Имя хоста или контейнера
-	// port	5432	Порт PostgreSQL (по умолчанию)
-	// database	postgres	Имя базы данных
-
+// NewPostgres открывает соединение с PostgreSQL по dsn и проверяет его.
+func NewPostgres(dsn string) (*sql.DB, error) {
 	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка открытия БД: %w", err)
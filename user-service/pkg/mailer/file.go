@@ -0,0 +1,30 @@
+package mailer
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// File пишет письма в w вместо отправки. Подходит для локальной
+// разработки и тестов: ссылку из письма можно взять из файла или лога.
+type File struct {
+	mu   sync.Mutex
+	w    io.Writer
+	from string
+}
+
+func NewFile(w io.Writer, from string) *File {
+	return &File{w: w, from: from}
+}
+
+func (f *File) Send(ctx context.Context, msg Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.w.Write(format(f.from, msg)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(f.w, "\r\n\r\n")
+	return err
+}
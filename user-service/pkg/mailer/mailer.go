@@ -0,0 +1,37 @@
+// Package mailer отправляет письма пользователям: SMTP в бою,
+// запись в файл или лог при локальной разработке.
+package mailer
+
+import (
+	"context"
+	"os"
+)
+
+// Message — простое текстовое письмо.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer отправляет письма.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New выбирает реализацию по настройкам: SMTP, если задан smtp.Addr,
+// иначе письма пишутся в файл file или в stdout.
+func New(smtp SMTPConfig, file string) (Mailer, error) {
+	if smtp.Addr != "" {
+		return NewSMTP(smtp), nil
+	}
+
+	if file != "" {
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		return NewFile(f, smtp.From), nil
+	}
+	return NewFile(os.Stdout, smtp.From), nil
+}
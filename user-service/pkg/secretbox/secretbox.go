@@ -0,0 +1,81 @@
+// Package secretbox шифрует небольшие секреты для хранения в базе,
+// например секреты TOTP. Алгоритм — AES-256-GCM, ключ задаётся в настройках.
+package secretbox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize — длина ключа в байтах.
+const KeySize = 32
+
+// prefix отличает зашифрованное значение от записанного до шифрования
+// и оставляет место для смены алгоритма.
+const prefix = "v1:"
+
+var ErrMalformed = errors.New("secretbox: malformed sealed value")
+
+// Box шифрует и расшифровывает значения одним ключом.
+type Box struct {
+	aead cipher.AEAD
+}
+
+// New принимает ключ из KeySize байт в base64.
+func New(key string) (*Box, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("secretbox: key is not base64: %w", err)
+	}
+	if len(raw) != KeySize {
+		return nil, fmt.Errorf("secretbox: key must be %d bytes, got %d", KeySize, len(raw))
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Box{aead: aead}, nil
+}
+
+// Seal шифрует plaintext. context привязывает значение к записи, например
+// к ID пользователя: перенесённое в другую запись значение не расшифруется.
+func (b *Box) Seal(plaintext, context string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(plaintext), []byte(context))
+	return prefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Open расшифровывает значение Seal с тем же context.
+func (b *Box) Open(sealed, context string) (string, error) {
+	encoded, ok := strings.CutPrefix(sealed, prefix)
+	if !ok {
+		return "", ErrMalformed
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil || len(raw) < b.aead.NonceSize() {
+		return "", ErrMalformed
+	}
+	nonce, ciphertext := raw[:b.aead.NonceSize()], raw[b.aead.NonceSize():]
+	plaintext, err := b.aead.Open(nil, nonce, ciphertext, []byte(context))
+	if err != nil {
+		return "", fmt.Errorf("secretbox: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// IsSealed сообщает, зашифровано ли значение, или оно записано до шифрования.
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
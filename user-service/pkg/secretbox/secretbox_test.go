@@ -0,0 +1,73 @@
+package secretbox
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+var testKey = base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+
+func TestSealOpen(t *testing.T) {
+	box, err := New(testKey)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	sealed, err := box.Seal("JBSWY3DPEHPK3PXP", "totp:1")
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if !IsSealed(sealed) || strings.Contains(sealed, "JBSWY3DPEHPK3PXP") {
+		t.Fatalf("Seal = %q, want an opaque sealed value", sealed)
+	}
+	again, _ := box.Seal("JBSWY3DPEHPK3PXP", "totp:1")
+	if again == sealed {
+		t.Errorf("two seals of the same value are equal, nonce is reused")
+	}
+
+	otherBox, _ := New(base64.StdEncoding.EncodeToString([]byte("fedcba9876543210fedcba9876543210")))
+	flipped := []byte(sealed)
+	flipped[len(flipped)-2] ^= 1
+
+	tests := []struct {
+		name    string
+		box     *Box
+		sealed  string
+		context string
+		wantErr bool
+	}{
+		{"same context", box, sealed, "totp:1", false},
+		{"other record", box, sealed, "totp:2", true},
+		{"other key", otherBox, sealed, "totp:1", true},
+		{"tampered", box, string(flipped), "totp:1", true},
+		{"plaintext", box, "JBSWY3DPEHPK3PXP", "totp:1", true},
+		{"truncated", box, prefix + "AAAA", "totp:1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.box.Open(tt.sealed, tt.context)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Open = %q, want error", got)
+				}
+				return
+			}
+			if err != nil || got != "JBSWY3DPEHPK3PXP" {
+				t.Errorf("Open = %q, %v", got, err)
+			}
+		})
+	}
+}
+
+func TestNewRejectsBadKeys(t *testing.T) {
+	for _, key := range []string{
+		"",
+		"not base64!",
+		base64.StdEncoding.EncodeToString([]byte("short")),
+		base64.StdEncoding.EncodeToString(make([]byte, 64)),
+	} {
+		if _, err := New(key); err == nil {
+			t.Errorf("New(%q) accepted a bad key", key)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret — ключ SHA1 из приложения B RFC 6238, "12345678901234567890" в base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// Векторы RFC 6238 для SHA1, последние Digits цифр восьмизначного кода.
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestCodeRFC6238(t *testing.T) {
+	for _, v := range rfcVectors {
+		got, err := Code(rfcSecret, Step(time.Unix(v.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code: %v", err)
+		}
+		if got != v.code {
+			t.Errorf("Code at %d = %s, want %s", v.unix, got, v.code)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	at := time.Unix(1111111111, 0)
+	step := Step(at)
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		t        time.Time
+		lastStep int64
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current step", rfcSecret, "050471", at, 0, step, true},
+		{"lowercase secret", "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", "050471", at, 0, step, true},
+		{"previous step within skew", rfcSecret, "050471", at.Add(Period), 0, step, true},
+		{"next step within skew", rfcSecret, "050471", at.Add(-Period), 0, step, true},
+		{"outside skew", rfcSecret, "050471", at.Add(2 * Period), 0, 0, false},
+		{"wrong code", rfcSecret, "123456", at, 0, 0, false},
+		{"short code", rfcSecret, "05047", at, 0, 0, false},
+		{"malformed secret", "not base32!", "050471", at, 0, 0, false},
+		{"replay of last step", rfcSecret, "050471", at, step, 0, false},
+		{"replay of older step", rfcSecret, "050471", at.Add(Period), step, 0, false},
+		{"after an older step", rfcSecret, "050471", at, step - 1, step, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, ok := Validate(tt.secret, tt.code, tt.t, tt.lastStep)
+			if ok != tt.wantOK || gotStep != tt.wantStep {
+				t.Errorf("Validate = (%d, %v), want (%d, %v)", gotStep, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
@@ -4,19 +4,31 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Murodkadirkhanoff/taqsym.uz/pkg/jwks"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var secret = []byte("supersecret")
+const (
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
 
-func GenerateToken(userID int64, email string) (string, error) {
+// GenerateToken выпускает access token сессии sessionID.
+func GenerateToken(keys *jwks.KeySet, userID int64, email, role string, permissions []string, sessionID string) (string, error) {
 	fmt.Println(userID)
+	jti, err := RandomID(16)
+	if err != nil {
+		return "", err
+	}
 	claims := jwt.MapClaims{
-		"user_id": userID,
-		"email":   email,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
+		"user_id":     userID,
+		"email":       email,
+		"role":        role,
+		"permissions": permissions,
+		"jti":         jti, // по нему токен можно отозвать до exp
+		"sid":         sessionID,
+		"exp":         time.Now().Add(AccessTokenTTL).Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(secret)
+	return keys.Sign(claims)
 }